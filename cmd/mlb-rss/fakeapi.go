@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/0queue/mlb-rss/internal/mlb/mlbtest"
+)
+
+// fakeApi serves fixtures as if it were statsapi, point mlb-rss at it with
+// MLB_API_URL=http://localhost:8081/api/v1 and TODAY set to a date in the fixtures
+func fakeApi(args []string) {
+	fs := flag.NewFlagSet("fake-api", flag.ExitOnError)
+	addr := fs.String("addr", ":8081", "address to listen on")
+	dir := fs.String("data", "test/data", "fixture directory")
+	fs.Parse(args)
+
+	slog.Info("Starting fake statsapi",
+		slog.String("addr", *addr),
+		slog.String("data", *dir),
+		slog.String("MLB_API_URL", "http://localhost"+*addr+mlbtest.Prefix),
+	)
+
+	err := http.ListenAndServe(*addr, mlbtest.Handler(os.DirFS(*dir)))
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"html"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/api"
+	"github.com/0queue/mlb-rss/internal/atom"
+	"github.com/0queue/mlb-rss/internal/jsonfeed"
+	"github.com/0queue/mlb-rss/internal/rss"
+	"github.com/0queue/mlb-rss/internal/tinycron"
+	"github.com/0queue/mlb-rss/ui"
+)
+
+type server struct {
+	c           config
+	feeds       *teamFeeds
+	defaultFeed *teamFeed
+	scheduler   *tinycron.Scheduler
+}
+
+func newServer(c config, feeds *teamFeeds, defaultFeed *teamFeed, scheduler *tinycron.Scheduler) *server {
+	return &server{
+		c:           c,
+		feeds:       feeds,
+		defaultFeed: defaultFeed,
+		scheduler:   scheduler,
+	}
+}
+
+type teamHandler func(w http.ResponseWriter, r *http.Request, f *teamFeed)
+
+// Mux serves every route at /teams/{abbr}/{route} and, as an alias
+// for the default team, at /{route}
+func (s *server) Mux() *http.ServeMux {
+	routes := map[string]teamHandler{
+		"":           s.serveWeb,
+		"rss.xml":    s.serveRss,
+		"atom.xml":   s.serveAtom,
+		"feed.json":  s.serveJsonFeed,
+		"api/report": s.serveApiReport,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		h, ok := routes[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		h(w, r, s.defaultFeed)
+	})
+	mux.HandleFunc("/teams/", func(w http.ResponseWriter, r *http.Request) {
+		abbr, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/teams/"), "/")
+
+		h, ok := routes[rest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		f, ok := s.feeds.GetByAbbr(abbr)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		h(w, r, f)
+	})
+	mux.HandleFunc("/api/jobs", s.serveApiJobs)
+	mux.HandleFunc("/favicon-32x32.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "image/png")
+		w.Write(ui.Favicon)
+	})
+
+	return mux
+}
+
+// feedItem is what rss, atom and json feed items are made of
+type feedItem struct {
+	// Key is unique within a team's feed, and stable across restarts
+	Key   string
+	Title string
+	Link  string
+	Html  string
+	When  time.Time
+	// Updated is zero unless the item changed after When
+	Updated time.Time
+}
+
+// updated is when the item last changed
+func (item feedItem) updated() time.Time {
+	if item.Updated.After(item.When) {
+		return item.Updated
+	}
+	return item.When
+}
+
+// feedItems renders the whole history of the feed, along with any live events, newest first
+func (s *server) feedItems(f *teamFeed) ([]feedItem, error) {
+	// starts generating today's report if it is missing, the feed
+	// makes do with the history until that is done
+	f.Report()
+
+	var all []feedItem
+	for _, cachedReport := range f.cache.All() {
+		rendered, err := f.rg.Render(cachedReport)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, feedItem{
+			Key:     cachedReport.ItemKey(),
+			Title:   cachedReport.Headline,
+			Link:    cachedReport.Link,
+			Html:    rendered,
+			When:    cachedReport.When,
+			Updated: cachedReport.Updated,
+		})
+	}
+
+	for _, e := range f.events.All() {
+		all = append(all, feedItem{
+			Key:   "live-" + e.Key,
+			Title: e.Title,
+			Link:  e.Link,
+			Html:  "<p>" + html.EscapeString(e.Summary) + "</p>",
+			When:  e.When,
+		})
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].When.After(all[j].When)
+	})
+
+	return all, nil
+}
+
+func (s *server) serveRss(w http.ResponseWriter, r *http.Request, f *teamFeed) {
+	items, err := s.feedItems(f)
+	if err != nil {
+		slog.Error("Failed to render report", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var rssItems []rss.Item
+	for _, item := range items {
+		rssItems = append(rssItems, rss.Item{
+			Title: item.Title,
+			Link:  item.Link,
+			Description: &rss.Description{
+				Text: item.Html,
+			},
+			// TODO investigate setting the Guid as prefix + hash(date + content)
+			//      to enable iteration on content, and seeing results immediately
+			//      after deploying and refreshing in miniflux
+			Guid:    "mlb-rss-" + item.Key,
+			PubDate: item.When.Format(time.RFC822),
+		})
+	}
+
+	feed := rss.Rss{
+		Version: "2.0",
+		Channel: rss.Channel{
+			Title:       "MLB RSS - " + f.Team.Name,
+			Link:        "https://baseball.theater",
+			Description: "Feed generated from statsapi.mlb.com",
+			Items:       rssItems,
+		},
+	}
+
+	// should probably just cache the xml
+	bytes, err := xml.MarshalIndent(feed, "", " ")
+	if err != nil {
+		slog.Error("Failed to marshal rss feed", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "application/rss+xml")
+	w.Write(bytes)
+}
+
+func (s *server) serveAtom(w http.ResponseWriter, r *http.Request, f *teamFeed) {
+	items, err := s.feedItems(f)
+	if err != nil {
+		slog.Error("Failed to render report", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// an empty feed was last updated... now I guess
+	updated := f.clock.Now()
+	for i, item := range items {
+		if i == 0 || item.updated().After(updated) {
+			updated = item.updated()
+		}
+	}
+
+	var entries []atom.Entry
+	for _, item := range items {
+		entries = append(entries, atom.Entry{
+			Id:        "urn:mlb-rss:" + f.Team.Abbreviation + ":" + item.Key,
+			Title:     item.Title,
+			Updated:   item.updated(),
+			Published: item.When,
+			Links: []atom.Link{
+				{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			},
+			Content: &atom.Content{
+				Type: "html",
+				Text: item.Html,
+			},
+		})
+	}
+
+	feed := atom.Feed{
+		Xmlns:    atom.Namespace,
+		Id:       "urn:mlb-rss:" + f.Team.Abbreviation,
+		Title:    "MLB RSS - " + f.Team.Name,
+		Subtitle: "Feed generated from statsapi.mlb.com",
+		Updated:  updated,
+		Links: []atom.Link{
+			{Href: "https://baseball.theater", Rel: "alternate", Type: "text/html"},
+		},
+		Author: &atom.Person{
+			Name: "mlb-rss",
+			Uri:  "https://github.com/0queue/mlb-rss",
+		},
+		Entries: entries,
+	}
+
+	bytes, err := xml.MarshalIndent(feed, "", " ")
+	if err != nil {
+		slog.Error("Failed to marshal atom feed", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "application/atom+xml")
+	w.Write([]byte(xml.Header))
+	w.Write(bytes)
+}
+
+func (s *server) serveJsonFeed(w http.ResponseWriter, r *http.Request, f *teamFeed) {
+	items, err := s.feedItems(f)
+	if err != nil {
+		slog.Error("Failed to render report", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	jsonItems := make([]jsonfeed.Item, 0, len(items))
+	for _, item := range items {
+		item := item
+		var dateModified *time.Time
+		if !item.Updated.IsZero() {
+			dateModified = &item.Updated
+		}
+
+		jsonItems = append(jsonItems, jsonfeed.Item{
+			// same as the rss guid
+			Id:            "mlb-rss-" + item.Key,
+			Url:           item.Link,
+			Title:         item.Title,
+			ContentHtml:   item.Html,
+			DatePublished: &item.When,
+			DateModified:  dateModified,
+		})
+	}
+
+	feed := jsonfeed.Feed{
+		Version:     jsonfeed.Version,
+		Title:       "MLB RSS - " + f.Team.Name,
+		HomePageUrl: "https://baseball.theater",
+		FeedUrl:     requestUrl(r),
+		Description: "Feed generated from statsapi.mlb.com",
+		Authors: []jsonfeed.Author{
+			{Name: "mlb-rss", Url: "https://github.com/0queue/mlb-rss"},
+		},
+		Items: jsonItems,
+	}
+
+	bytes, err := json.MarshalIndent(feed, "", " ")
+	if err != nil {
+		slog.Error("Failed to marshal json feed", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "application/feed+json")
+	w.Write(bytes)
+}
+
+func (s *server) serveApiReport(w http.ResponseWriter, r *http.Request, f *teamFeed) {
+	cachedReport, ok := f.Report()
+	if !ok {
+		notReady(w)
+		return
+	}
+
+	bytes, err := json.Marshal(api.FromReport(f.Team, cachedReport))
+	if err != nil {
+		slog.Error("Failed to marshal api report", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "application/json")
+	w.Write(bytes)
+}
+
+func (s *server) serveWeb(w http.ResponseWriter, r *http.Request, f *teamFeed) {
+	cachedReport, ok := f.Report()
+	if !ok {
+		notReady(w)
+		return
+	}
+
+	rendered, err := f.rg.RenderWeb(cachedReport)
+	if err != nil {
+		slog.Error("Failed to render web", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "text/html")
+	w.Write([]byte(rendered))
+}
+
+// notReady is for a feed that has no report yet, which is being generated
+// in the background, or will be once the refresh job gets to it
+func notReady(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "60")
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
+// requestUrl reconstructs the absolute url of r, as best as it can from behind a proxy
+func requestUrl(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + r.URL.Path
+}
+
+// apiJob is a tinycron.JobStatus, with times left out until there is one
+type apiJob struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	LastRun  *time.Time `json:"lastRun,omitempty"`
+	NextRun  *time.Time `json:"nextRun,omitempty"`
+}
+
+// serveApiJobs shows when the background jobs last ran, and will run next
+func (s *server) serveApiJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := make([]apiJob, 0)
+	for _, js := range s.scheduler.Jobs() {
+		js := js
+		j := apiJob{
+			Name:     js.Name,
+			Schedule: js.Schedule,
+		}
+		if !js.LastRun.IsZero() {
+			j.LastRun = &js.LastRun
+		}
+		if !js.NextRun.IsZero() {
+			j.NextRun = &js.NextRun
+		}
+		jobs = append(jobs, j)
+	}
+
+	bytes, err := json.Marshal(jobs)
+	if err != nil {
+		slog.Error("Failed to marshal jobs", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("content-type", "application/json")
+	w.Write(bytes)
+}
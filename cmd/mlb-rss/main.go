@@ -2,30 +2,68 @@ package main
 
 import (
 	"context"
-	"encoding/xml"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/0queue/mlb-rss/internal/cache"
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/live"
 	"github.com/0queue/mlb-rss/internal/mlb"
 	"github.com/0queue/mlb-rss/internal/report"
-	"github.com/0queue/mlb-rss/internal/rss"
 	"github.com/0queue/mlb-rss/internal/tinycron"
-	"github.com/0queue/mlb-rss/ui"
 )
 
 type config struct {
 	JsonLog     bool
 	Addr        string
 	CheckAtHour int
-	MyTeam      string
-	Offseason   bool
+	// RefreshCron is when reports are generated, CHECK_AT_HOUR o'clock by default
+	RefreshCron string
+	// RefreshJitter spreads out the refresh, to be nice to statsapi
+	RefreshJitter time.Duration
+	MyTeam        string
+	// MyTeams are refreshed by the cron job along with MyTeam, any other
+	// team is generated in the background whenever its feed is requested
+	// and doesn't have today's report yet
+	MyTeams []string
+	// HistoryDays is how many daily reports are kept in the feed
+	HistoryDays int
+	// Store is where reports are persisted: none, json or bolt
+	Store     string
+	StorePath string
+	// Live polls games while they are on, adding events to the feed
+	Live     bool
+	LivePoll time.Duration
+	// BoxScore adds pitching and batting lines to the report
+	BoxScore bool
+	// RetryEvery and RetryFor control retrying failed or incomplete reports
+	RetryEvery time.Duration
+	RetryFor   time.Duration
+	// CondensedCheck is how often to look for condensed games missing from today's report
+	CondensedCheck time.Duration
+	// CondensedNewItem publishes a report updated with a condensed game as a new item
+	CondensedNewItem bool
+	// MlbApiUrl replaces statsapi.mlb.com, to use a fake-api for example
+	MlbApiUrl string
+	// Today pins the date for development against fixtures, zero means the real date
+	Today time.Time
+}
+
+// clock is the real time, but on Today if it is set
+func (c config) clock() clock.Clock {
+	if c.Today.IsZero() {
+		return clock.System
+	}
+
+	return clock.OnDay(c.Today)
 }
 
 func readConfigFromEnv() config {
@@ -48,23 +86,116 @@ func readConfigFromEnv() config {
 		checkAtHour = 7
 	}
 
+	refreshCron := os.Getenv("REFRESH_CRON")
+	if refreshCron == "" {
+		refreshCron = fmt.Sprintf("0 %d * * *", checkAtHour)
+	}
+
+	refreshJitterMinutes, err := strconv.Atoi(os.Getenv("REFRESH_JITTER_MINUTES"))
+	if err != nil || refreshJitterMinutes < 0 {
+		refreshJitterMinutes = 0
+	}
+
 	myTeam := os.Getenv("MY_TEAM")
 	if myTeam == "" {
 		myTeam = "BAL"
 	}
 
-	offseason := strings.ToLower(os.Getenv("OFFSEASON")) == "true"
+	myTeams := []string{myTeam}
+	for _, t := range strings.Split(os.Getenv("MY_TEAMS"), ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			myTeams = append(myTeams, t)
+		}
+	}
+
+	historyDaysRaw := os.Getenv("HISTORY_DAYS")
+	if historyDaysRaw == "" {
+		historyDaysRaw = "7"
+	}
+	historyDays, err := strconv.Atoi(historyDaysRaw)
+	if err != nil || historyDays < 1 {
+		historyDays = 7
+	}
+
+	store := strings.ToLower(os.Getenv("STORE"))
+	if store != "json" && store != "bolt" {
+		store = "none"
+	}
+
+	storePath := os.Getenv("STORE_PATH")
+	if storePath == "" && store == "json" {
+		storePath = "data"
+	} else if storePath == "" && store == "bolt" {
+		storePath = "mlb-rss.db"
+	}
+
+	liveMode := strings.ToLower(os.Getenv("LIVE")) == "true"
+
+	livePollSeconds, err := strconv.Atoi(os.Getenv("LIVE_POLL_SECONDS"))
+	if err != nil || livePollSeconds < 10 {
+		livePollSeconds = 60
+	}
+
+	boxScore := strings.ToLower(os.Getenv("BOX_SCORE")) == "true"
+
+	retryEveryMinutes, err := strconv.Atoi(os.Getenv("RETRY_EVERY_MINUTES"))
+	if err != nil || retryEveryMinutes < 1 {
+		retryEveryMinutes = 15
+	}
+
+	retryForHours, err := strconv.Atoi(os.Getenv("RETRY_FOR_HOURS"))
+	if err != nil || retryForHours < 0 {
+		retryForHours = 3
+	}
+
+	condensedCheckMinutes, err := strconv.Atoi(os.Getenv("CONDENSED_CHECK_MINUTES"))
+	if err != nil || condensedCheckMinutes < 0 {
+		condensedCheckMinutes = 30
+	}
+
+	condensedNewItem := strings.ToLower(os.Getenv("CONDENSED_NEW_ITEM")) == "true"
+
+	mlbApiUrl := os.Getenv("MLB_API_URL")
+
+	// ignored if it doesn't parse
+	today, _ := time.ParseInLocation(time.DateOnly, os.Getenv("TODAY"), time.Local)
 
 	return config{
-		JsonLog:     jsonLog,
-		Addr:        addr,
-		CheckAtHour: checkAtHour,
-		MyTeam:      myTeam,
-		Offseason:   offseason,
+		JsonLog:          jsonLog,
+		Addr:             addr,
+		CheckAtHour:      checkAtHour,
+		RefreshCron:      refreshCron,
+		RefreshJitter:    time.Duration(refreshJitterMinutes) * time.Minute,
+		MyTeam:           myTeam,
+		MyTeams:          myTeams,
+		HistoryDays:      historyDays,
+		Store:            store,
+		StorePath:        storePath,
+		Live:             liveMode,
+		LivePoll:         time.Duration(livePollSeconds) * time.Second,
+		BoxScore:         boxScore,
+		RetryEvery:       time.Duration(retryEveryMinutes) * time.Minute,
+		RetryFor:         time.Duration(retryForHours) * time.Hour,
+		CondensedCheck:   time.Duration(condensedCheckMinutes) * time.Minute,
+		CondensedNewItem: condensedNewItem,
+		MlbApiUrl:        mlbApiUrl,
+		Today:            today,
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fake-api":
+			fakeApi(os.Args[2:])
+			return
+		case "record":
+			record(os.Args[2:])
+			return
+		}
+	}
+
 	// read config
 	c := readConfigFromEnv()
 
@@ -76,127 +207,172 @@ func main() {
 	}
 	slog.SetDefault(slog.New(handler))
 
-	slog.Info("configuration successful", slog.Int("CHECK_AT_HOUR", c.CheckAtHour))
+	slog.Info("configuration successful",
+		slog.Int("CHECK_AT_HOUR", c.CheckAtHour),
+		slog.String("REFRESH_CRON", c.RefreshCron),
+		slog.Duration("REFRESH_JITTER_MINUTES", c.RefreshJitter),
+		slog.Int("HISTORY_DAYS", c.HistoryDays),
+		slog.String("STORE", c.Store),
+		slog.String("STORE_PATH", c.StorePath),
+		slog.Bool("LIVE", c.Live),
+		slog.Bool("BOX_SCORE", c.BoxScore),
+		slog.Duration("RETRY_EVERY_MINUTES", c.RetryEvery),
+		slog.Duration("RETRY_FOR_HOURS", c.RetryFor),
+		slog.Duration("CONDENSED_CHECK_MINUTES", c.CondensedCheck),
+		slog.Bool("CONDENSED_NEW_ITEM", c.CondensedNewItem),
+		slog.String("MLB_API_URL", c.MlbApiUrl),
+	)
 
-	mc, err := mlb.NewMlbClient()
+	// prepare shutdown channel
+	// this signalCtx goes to the mlb client and the jobs
+	// not the http server though, because it is already cancelled
+	signalCtx, signalCancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer signalCancel()
+
+	mc, err := mlb.NewMlbClient(mlb.WithBaseUrl(c.MlbApiUrl), mlb.WithContext(signalCtx))
 	if err != nil {
 		slog.Error(err.Error())
 		os.Exit(1)
 	}
 
-	myTeam, ok := mc.FindTeam(c.MyTeam)
-	if !ok {
-		slog.Error("Failed to find team", slog.String("team", c.MyTeam))
-		os.Exit(1)
+	var newStore func(team mlb.Team) (cache.Store[report.Report], error)
+	switch c.Store {
+	case "json":
+		newStore = func(team mlb.Team) (cache.Store[report.Report], error) {
+			return cache.NewJsonStore[report.Report](filepath.Join(c.StorePath, team.Abbreviation))
+		}
+	case "bolt":
+		db, err := cache.OpenBolt(c.StorePath)
+		if err != nil {
+			slog.Error("Failed to open bolt database", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		newStore = func(team mlb.Team) (cache.Store[report.Report], error) {
+			return cache.NewBoltStore[report.Report](db, team.Abbreviation)
+		}
 	}
-	rg := report.NewReportGenerator(myTeam.Id, mc, time.Local)
 
-	// seed cache
-	cache := cache.Cache[report.Report]{}
-	// prepare shutdown channel
-	// this signalCtx goes to the report generator
-	// not the http server though, because it is already cancelled
-	signalCtx, signalCancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer signalCancel()
+	clk := c.clock()
+	cal := report.NewCalendar(mc)
 
-	// start refresh cron job
-	tinycron.EveryDay(signalCtx, c.CheckAtHour, func() {
-		if c.Offseason {
-			slog.Info("No more baseball, go to sleep!")
-			return
-		}
+	newGenerator := func(team mlb.Team) report.ReportGenerator {
+		rg := report.NewReportGenerator(team.Id, mc, time.Local)
+		rg.Clock = clk
+		rg.Calendar = cal
+		rg.BoxScore = c.BoxScore
+		return rg
+	}
 
-		now := time.Now()
-		slog.Info("Updating cache", slog.Time("now", now))
+	feeds := newTeamFeeds(mc, clk, c.HistoryDays, newGenerator, newStore)
+	// every feed gets watched from the moment it is created, on demand ones included
+	if c.Live {
+		feeds.watch = func(f *teamFeed) {
+			live.Watch(signalCtx, mc, clk, f.Team.Id, c.LivePoll, f.AddEvent)
+		}
+	}
 
-		r, err := rg.GenerateReport(now)
-		if err != nil {
-			slog.Error("Failed to generate report", slog.String("err", err.Error()))
-			return
+	defaultFeed, ok := feeds.Schedule(c.MyTeam)
+	if !ok {
+		slog.Error("Failed to find team", slog.String("team", c.MyTeam))
+		os.Exit(1)
+	}
+	for _, t := range c.MyTeams {
+		if _, ok := feeds.Schedule(t); !ok {
+			slog.Error("Failed to find team", slog.String("team", t))
+			os.Exit(1)
 		}
+	}
 
-		cache.Set(r)
-	})
+	scheduler := tinycron.New(clk)
 
-	// serve xml
-	mux := http.NewServeMux()
-	mux.HandleFunc("/rss.xml", func(w http.ResponseWriter, r *http.Request) {
-		var items []rss.Item
+	refreshCron, err := tinycron.Parse(c.RefreshCron, time.Local)
+	if err != nil {
+		slog.Error("Failed to parse REFRESH_CRON", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
 
-		cachedReport, ok := cache.Get()
-		if ok {
-			rendered, err := rg.Render(cachedReport)
+	// start refresh cron job, which tries again until every team's report is complete
+	retry := tinycron.RetryPolicy{
+		Every: c.RetryEvery,
+		For:   c.RetryFor,
+	}
+	err = scheduler.Add(signalCtx, tinycron.Job{
+		Name:     "refresh",
+		Schedule: refreshCron,
+		Jitter:   c.RefreshJitter,
+		// a report a few hours late beats no report
+		Missed:     tinycron.RunMissed,
+		Retry:      retry,
+		RunOnStart: true,
+		Run: func() bool {
+			now := clk.Now()
+
+			// without the season's dates, carry on as if there is baseball
+			phase, _, err := cal.Phase(now)
 			if err != nil {
-				slog.Error("Failed to render report", slog.String("err", err.Error()))
-				w.WriteHeader(http.StatusInternalServerError)
-				return
+				slog.Warn("Failed to fetch season", slog.String("err", err.Error()))
 			}
 
-			items = append(items, rss.Item{
-				Title: cachedReport.Headline,
-				Link:  cachedReport.Link,
-				Description: &rss.Description{
-					Text: rendered,
-				},
-				// TODO investigate setting the Guid as prefix + hash(date + content)
-				//      to enable iteration on content, and seeing results immediately
-				//      after deploying and refreshing in miniflux
-				Guid:    "mlb-rss-" + cachedReport.When.Format(report.BaseballTheaterTimeFormat),
-				PubDate: cachedReport.When.Format(time.RFC822),
-			})
-		}
-
-		feed := rss.Rss{
-			Version: "2.0",
-			Channel: rss.Channel{
-				Title:       "MLB RSS",
-				Link:        "https://baseball.theater",
-				Description: "Feed generated from statsapi.mlb.com",
-				Items:       items,
-			},
-		}
-
-		// should probably just cache the xml
-		bytes, err := xml.MarshalIndent(feed, "", " ")
-		if err != nil {
-			slog.Error("Failed to marshal rss feed", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+			done := true
+			for _, f := range feeds.Scheduled() {
+				// the offseason only gets a countdown once a week
+				if phase == mlb.PhaseOffseason && f.Recent(now, offseasonDays) {
+					slog.Info("No more baseball, go to sleep!", slog.String("team", f.Team.Abbreviation))
+					continue
+				}
+
+				if f.Complete(now) {
+					continue
+				}
+
+				err := f.Refresh()
+				if err != nil {
+					slog.Error("Failed to generate report",
+						slog.String("team", f.Team.Abbreviation),
+						slog.String("err", err.Error()),
+					)
+				}
+
+				if !f.Complete(now) {
+					slog.Info("Report is not complete yet", slog.String("team", f.Team.Abbreviation))
+					done = false
+				}
+			}
 
-		w.Header().Add("content-type", "application/rss+xml")
-		w.Write(bytes)
+			return done
+		},
 	})
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if c.Offseason {
-			w.Write([]byte("Offseason! 💤"))
-			return
-		}
-
-		cachedReport, ok := cache.Get()
-		if !ok {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
+	if err != nil {
+		slog.Error("Failed to add refresh job", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
 
-		rendered, err := rg.RenderWeb(cachedReport)
+	// condensed games often show up after the retries have given up
+	if c.CondensedCheck > 0 {
+		err = scheduler.Add(signalCtx, tinycron.Job{
+			Name:     "condensed",
+			Schedule: tinycron.Interval(c.CondensedCheck),
+			// the next check is never far off
+			Missed: tinycron.SkipMissed,
+			Run: func() bool {
+				now := clk.Now()
+				for _, f := range feeds.Scheduled() {
+					f.UpdateCondensedGames(now, c.CondensedNewItem)
+				}
+				return true
+			},
+		})
 		if err != nil {
-			slog.Error("Failed to render web", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			slog.Error("Failed to add condensed job", slog.String("err", err.Error()))
+			os.Exit(1)
 		}
-
-		w.Header().Add("content-type", "text/html")
-		w.Write([]byte(rendered))
-	})
-	mux.HandleFunc("/favicon-32x32.png", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("content-type", "image/png")
-		w.Write(ui.Favicon)
-	})
+	}
 
 	server := http.Server{
 		Addr:    c.Addr,
-		Handler: mux,
+		Handler: newServer(c, feeds, defaultFeed, scheduler).Mux(),
 	}
 
 	slog.Info("Starting http server", slog.String("addr", c.Addr))
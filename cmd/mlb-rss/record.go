@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/mlb/mlbtest"
+	"github.com/0queue/mlb-rss/internal/report"
+)
+
+// record generates the report for a day against the real statsapi, saving
+// every response along the way as fixtures. Serve them with fake-api --data,
+// or replay them in tests with mlbtest.Replay
+func record(args []string) {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	date := fs.String("date", "", "day of the report to record, yyyy-mm-dd")
+	team := fs.String("team", "BAL", "team to record the report of")
+	out := fs.String("out", "", "fixture directory, defaults to test/data/recorded/{team}-{date}")
+	fs.Parse(args)
+
+	today, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "--date must be yyyy-mm-dd")
+		os.Exit(2)
+	}
+
+	if *out == "" {
+		*out = filepath.Join("test", "data", "recorded", *team+"-"+*date)
+	}
+
+	mc, err := mlb.NewMlbClient(
+		mlb.WithBaseUrl(os.Getenv("MLB_API_URL")),
+		mlb.WithTransport(mlbtest.Record(*out, nil)),
+	)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	myTeam, ok := mc.FindTeam(*team)
+	if !ok {
+		slog.Error("Failed to find team", slog.String("team", *team))
+		os.Exit(1)
+	}
+
+	// record everything there is, even the optional bits
+	rg := report.NewReportGenerator(myTeam.Id, mc, time.Local)
+	rg.BoxScore = true
+
+	r, err := rg.GenerateReport(today)
+	if err != nil {
+		slog.Error("Failed to generate report", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+
+	slog.Info("Recorded report", slog.String("headline", r.Headline), slog.String("out", *out))
+}
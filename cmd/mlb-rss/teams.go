@@ -0,0 +1,340 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/cache"
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/live"
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report"
+)
+
+// maxEvents is plenty for a few days of games
+const maxEvents = 50
+
+// offseasonDays is how often there is a report in the offseason
+const offseasonDays = 7
+
+// onDemandRetry is how long a failed on demand report keeps requests from
+// trying again, so hitting a broken feed doesn't hammer statsapi
+const onDemandRetry = 5 * time.Minute
+
+// teamFeed is everything needed to serve the feed for a single team
+type teamFeed struct {
+	Team  mlb.Team
+	rg    report.ReportGenerator
+	clock clock.Clock
+	cache *cache.Cache[report.Report]
+	// events are only filled in live mode
+	events *cache.Cache[live.Event]
+	// scheduled feeds are kept up to date by the jobs, any other feed only
+	// when it is asked for. Only set by Schedule, before the server starts
+	scheduled bool
+	// only one report generation at a time per team
+	m sync.Mutex
+	// demand guards generating and failedAt, which are only for on demand reports
+	demand     sync.Mutex
+	generating bool
+	failedAt   time.Time
+}
+
+// Refresh generates a new report for today and stores it in the cache
+func (f *teamFeed) Refresh() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return f.refresh()
+}
+
+func (f *teamFeed) refresh() error {
+	slog.Info("Updating cache", slog.String("team", f.Team.Abbreviation))
+
+	r, err := f.rg.Generate()
+	if err != nil {
+		return err
+	}
+
+	// regenerating a report (after a restart, or a retry for example)
+	// shouldn't change its pubDate in the feed, it is an update
+	generated := r.When
+	old, regenerated := f.cache.Get()
+	regenerated = regenerated && old.Key() == r.Key()
+	if regenerated {
+		r.When = old.When
+		r.Revision = old.Revision
+		r.Updated = old.Updated
+	}
+
+	if previous, ok := f.previous(r.Key()); ok {
+		r = f.rg.RosterMoves(r, previous)
+	}
+
+	// and only counts as one when readers would see a difference
+	if regenerated && !f.sameContent(old, r) {
+		r.Updated = generated
+	}
+
+	f.cache.Set(r)
+	return nil
+}
+
+// sameContent is true when a and b would look the same in the feed
+func (f *teamFeed) sameContent(a, b report.Report) bool {
+	if a.Headline != b.Headline || a.Link != b.Link {
+		return false
+	}
+
+	ac, err := f.rg.Render(a)
+	if err != nil {
+		return false
+	}
+	bc, err := f.rg.Render(b)
+	if err != nil {
+		return false
+	}
+
+	return ac == bc
+}
+
+// previous is the newest report from before the day of key
+func (f *teamFeed) previous(key string) (report.Report, bool) {
+	for _, r := range f.cache.All() {
+		if r.Key() < key {
+			return r, true
+		}
+	}
+
+	return report.Report{}, false
+}
+
+// UpdateCondensedGames checks whether today's report is still missing condensed
+// games that have since been published. If newItem is set the updated report
+// gets a new revision, and so shows up as a new item in the feed
+func (f *teamFeed) UpdateCondensedGames(now time.Time, newItem bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	r, ok := f.cache.Get()
+	if !ok || r.Key() != now.Format(report.BaseballTheaterTimeFormat) {
+		return
+	}
+
+	r, updated := f.rg.UpdateCondensedGames(r)
+	if !updated {
+		return
+	}
+
+	r.Updated = now
+	if newItem {
+		r.Revision += 1
+		r.When = now
+	}
+
+	slog.Info("Updated condensed games",
+		slog.String("team", f.Team.Abbreviation),
+		slog.Int("revision", r.Revision),
+	)
+	f.cache.Set(r)
+}
+
+// Complete is true when the cached report is for today, and has everything in it
+func (f *teamFeed) Complete(now time.Time) bool {
+	r, ok := f.cache.Get()
+	return ok && r.Key() == now.Format(report.BaseballTheaterTimeFormat) && !r.Incomplete()
+}
+
+// Recent is true when the newest report is less than days old
+func (f *teamFeed) Recent(now time.Time, days int) bool {
+	r, ok := f.cache.Get()
+	// keys are yyyymmdd, which compare just fine as strings
+	return ok && r.Key() > now.AddDate(0, 0, -days).Format(report.BaseballTheaterTimeFormat)
+}
+
+// AddEvent keeps the first sighting of every live event,
+// so its time doesn't change on every poll
+func (f *teamFeed) AddEvent(e live.Event) {
+	if _, ok := f.events.Lookup(e.Key); ok {
+		return
+	}
+
+	slog.Info("Live event", slog.String("team", f.Team.Abbreviation), slog.String("key", e.Key))
+	f.events.Set(e)
+}
+
+// Report returns the newest cached report. A feed that isn't scheduled
+// generates today's report in the background when it is missing, and
+// until that is done this is the report before it, or nothing
+func (f *teamFeed) Report() (report.Report, bool) {
+	r, ok := f.cache.Get()
+	if !f.scheduled && (!ok || r.Key() != f.clock.Now().Format(report.BaseballTheaterTimeFormat)) {
+		f.generate()
+	}
+
+	return r, ok
+}
+
+// generate refreshes the feed in the background, unless it already is,
+// or it failed to not long ago
+func (f *teamFeed) generate() {
+	f.demand.Lock()
+	defer f.demand.Unlock()
+
+	if f.generating || (!f.failedAt.IsZero() && f.clock.Now().Sub(f.failedAt) < onDemandRetry) {
+		return
+	}
+	f.generating = true
+
+	go func() {
+		err := f.Refresh()
+
+		f.demand.Lock()
+		defer f.demand.Unlock()
+
+		f.generating = false
+		if err != nil {
+			f.failedAt = f.clock.Now()
+			slog.Error("Failed to generate report on demand",
+				slog.String("team", f.Team.Abbreviation),
+				slog.String("err", err.Error()),
+			)
+		}
+	}()
+}
+
+// teamFeeds lazily creates a teamFeed for every team that is asked for
+type teamFeeds struct {
+	m     sync.Mutex
+	mc    *mlb.MlbClient
+	clock clock.Clock
+	// historyDays is how many reports each feed keeps
+	historyDays  int
+	newGenerator func(team mlb.Team) report.ReportGenerator
+	// newStore is optional, and makes the Store backing a team's cache
+	newStore func(team mlb.Team) (cache.Store[report.Report], error)
+	// watch is optional, and starts live mode for a new feed
+	watch func(f *teamFeed)
+	feeds map[int]*teamFeed
+}
+
+func newTeamFeeds(
+	mc *mlb.MlbClient,
+	clk clock.Clock,
+	historyDays int,
+	newGenerator func(team mlb.Team) report.ReportGenerator,
+	newStore func(team mlb.Team) (cache.Store[report.Report], error),
+) *teamFeeds {
+	return &teamFeeds{
+		mc:           mc,
+		clock:        clk,
+		historyDays:  historyDays,
+		newGenerator: newGenerator,
+		newStore:     newStore,
+		feeds:        make(map[int]*teamFeed),
+	}
+}
+
+// Get finds the feed for the team matching q (see mlb.MlbClient.FindTeam),
+// creating it if this is the first time the team has been asked for
+func (tf *teamFeeds) Get(q string) (*teamFeed, bool) {
+	team, ok := tf.mc.FindTeam(q)
+	if !ok {
+		return nil, false
+	}
+
+	return tf.getTeam(team), true
+}
+
+// Schedule is like Get, and also has the jobs keep the feed up to date
+func (tf *teamFeeds) Schedule(q string) (*teamFeed, bool) {
+	f, ok := tf.Get(q)
+	if !ok {
+		return nil, false
+	}
+
+	tf.m.Lock()
+	defer tf.m.Unlock()
+
+	f.scheduled = true
+	return f, true
+}
+
+// GetByAbbr is like Get, but only matches on the team abbreviation
+func (tf *teamFeeds) GetByAbbr(abbr string) (*teamFeed, bool) {
+	team, ok := tf.mc.FindTeam(abbr)
+	if !ok || !strings.EqualFold(team.Abbreviation, abbr) {
+		return nil, false
+	}
+
+	return tf.getTeam(team), true
+}
+
+func (tf *teamFeeds) getTeam(team mlb.Team) *teamFeed {
+	tf.m.Lock()
+	defer tf.m.Unlock()
+
+	f, ok := tf.feeds[team.Id]
+	if !ok {
+		slog.Info("Adding team feed", slog.String("team", team.Abbreviation))
+		f = &teamFeed{
+			Team:   team,
+			rg:     tf.newGenerator(team),
+			clock:  tf.clock,
+			cache:  cache.NewCache(tf.historyDays, report.Report.Key),
+			events: cache.NewCache(maxEvents, func(e live.Event) string { return e.Key }),
+		}
+		if tf.newStore != nil {
+			tf.persist(f)
+		}
+		if tf.watch != nil {
+			tf.watch(f)
+		}
+		tf.feeds[team.Id] = f
+	}
+
+	return f
+}
+
+// persist loads previously stored reports into the feed's cache,
+// failing to do so just means starting from scratch
+func (tf *teamFeeds) persist(f *teamFeed) {
+	s, err := tf.newStore(f.Team)
+	if err == nil {
+		err = f.cache.Persist(s)
+	}
+	if err != nil {
+		slog.Error("Failed to load stored reports",
+			slog.String("team", f.Team.Abbreviation),
+			slog.String("err", err.Error()),
+		)
+		return
+	}
+
+	slog.Info("Loaded stored reports",
+		slog.String("team", f.Team.Abbreviation),
+		slog.Int("count", len(f.cache.All())),
+	)
+}
+
+// Scheduled returns the feeds the jobs keep up to date, sorted by abbreviation
+func (tf *teamFeeds) Scheduled() []*teamFeed {
+	tf.m.Lock()
+	defer tf.m.Unlock()
+
+	all := make([]*teamFeed, 0, len(tf.feeds))
+	for _, f := range tf.feeds {
+		if f.scheduled {
+			all = append(all, f)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Team.Abbreviation < all[j].Team.Abbreviation
+	})
+
+	return all
+}
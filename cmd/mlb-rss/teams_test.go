@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/cache"
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/live"
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+const (
+	bal = 110
+	nyy = 147
+)
+
+func testFeed(t *testing.T, src report.Source, clk clock.Clock) *teamFeed {
+	t.Helper()
+
+	rg := report.NewReportGenerator(bal, src, time.UTC)
+	rg.Clock = clk
+
+	team, _ := src.Team(bal)
+	return &teamFeed{
+		Team:   team,
+		rg:     rg,
+		clock:  clk,
+		cache:  cache.NewCache(7, report.Report.Key),
+		events: cache.NewCache(maxEvents, func(e live.Event) string { return e.Key }),
+	}
+}
+
+func TestRefreshUpdated(t *testing.T) {
+	src, err := reporttest.NewSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := mlb.Game{
+		GamePk: 1,
+		Status: mlb.Status{AbstractGameState: "Final", CodedGameState: "F", DetailedState: "Final"},
+	}
+	g.Teams.Away.Team.Id = nyy
+	g.Teams.Away.Score = 2
+	g.Teams.Home.Team.Id = bal
+	g.Teams.Home.Score = 5
+	g.Teams.Home.IsWinner = true
+	src.AddGame("2022-05-28", g)
+
+	clk := clock.NewFake(time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC))
+	f := testFeed(t, src, clk)
+
+	err = f.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := f.cache.Get()
+	if !first.Updated.IsZero() {
+		t.Errorf("got a new report updated at %v", first.Updated)
+	}
+
+	// a retry that finds nothing new
+	clk.Advance(15 * time.Minute)
+	err = f.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := f.cache.Get()
+	if !r.When.Equal(first.When) {
+		t.Errorf("got when %v, want %v", r.When, first.When)
+	}
+	if !r.Updated.IsZero() {
+		t.Errorf("got an identical report updated at %v", r.Updated)
+	}
+
+	// the linescore shows up
+	clk.Advance(15 * time.Minute)
+	src.Linescores[1] = mlb.Linescore{
+		Innings: []mlb.Inning{{Num: 1, Away: mlb.Stats{Runs: 2}, Home: mlb.Stats{Runs: 5}}},
+	}
+	err = f.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ = f.cache.Get()
+	if want := clk.Now(); !r.Updated.Equal(want) {
+		t.Errorf("got updated %v, want %v", r.Updated, want)
+	}
+
+	// and stays put once it has been seen
+	updated := r.Updated
+	clk.Advance(15 * time.Minute)
+	err = f.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ = f.cache.Get()
+	if !r.Updated.Equal(updated) {
+		t.Errorf("got updated %v, want %v", r.Updated, updated)
+	}
+}
+
+// failingSource fails to fetch the schedule while fail is set
+type failingSource struct {
+	*reporttest.Source
+	fail atomic.Bool
+}
+
+func (s *failingSource) FetchSchedule(start, end time.Time, teamId int) (mlb.Schedule, error) {
+	if s.fail.Load() {
+		return mlb.Schedule{}, errors.New("statsapi is down")
+	}
+	return s.Source.FetchSchedule(start, end, teamId)
+}
+
+// waitGenerated waits for the feed's background generation, if any, to finish
+func waitGenerated(t *testing.T, f *teamFeed) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f.demand.Lock()
+		generating := f.generating
+		f.demand.Unlock()
+
+		if !generating {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("still generating")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReportOnDemand(t *testing.T) {
+	rs, err := reporttest.NewSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &failingSource{Source: rs}
+	src.fail.Store(true)
+
+	clk := clock.NewFake(time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC))
+	f := testFeed(t, src, clk)
+
+	// nothing yet, and nothing to wait for
+	if _, ok := f.Report(); ok {
+		t.Fatal("got a report before generating one")
+	}
+	waitGenerated(t, f)
+
+	// a failure holds off the next try for a while, by the feed's clock
+	src.fail.Store(false)
+	clk.Advance(onDemandRetry - time.Second)
+	if _, ok := f.Report(); ok {
+		t.Fatal("got a report after failing to generate one")
+	}
+	waitGenerated(t, f)
+	if _, ok := f.cache.Get(); ok {
+		t.Fatal("tried again too soon")
+	}
+
+	clk.Advance(time.Second)
+	f.Report()
+	waitGenerated(t, f)
+	r, ok := f.Report()
+	if !ok {
+		t.Fatal("no report after trying again")
+	}
+
+	// today's report is there, so that's it until tomorrow
+	f.Report()
+	f.demand.Lock()
+	generating := f.generating
+	f.demand.Unlock()
+	if generating {
+		t.Error("generating again with today's report in the cache")
+	}
+
+	clk.Advance(24 * time.Hour)
+	if got, ok := f.Report(); !ok || got.Key() != r.Key() {
+		t.Errorf("got %q, want yesterday's %q while generating today's", got.Key(), r.Key())
+	}
+	waitGenerated(t, f)
+	if got, _ := f.Report(); got.Key() != "20220530" {
+		t.Errorf("got %q, want today's report", got.Key())
+	}
+}
+
+func TestScheduledNotOnDemand(t *testing.T) {
+	src, err := reporttest.NewSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := testFeed(t, src, clock.NewFake(time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC)))
+	f.scheduled = true
+
+	// the refresh job takes care of it
+	if _, ok := f.Report(); ok {
+		t.Fatal("got a report before generating one")
+	}
+	f.demand.Lock()
+	defer f.demand.Unlock()
+	if f.generating {
+		t.Error("generating a scheduled feed on demand")
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/0queue/mlb-rss
 
-go 1.18
+go 1.21
+
+require go.etcd.io/bbolt v1.3.8
+
+require golang.org/x/sys v0.7.0 // indirect
@@ -0,0 +1,549 @@
+// Package api is the json representation of a report.Report served at /api/report.
+//
+// The report package is free to change shape as the templates need,
+// these types are not: fields are only ever added, never renamed or removed,
+// and SchemaVersion is bumped if that promise ever has to be broken.
+package api
+
+import (
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report"
+)
+
+const SchemaVersion = 1
+
+type Report struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// Id is the same as the date part of the feed item ids, yyyymmdd
+	Id string `json:"id"`
+	// Revision goes up when the report is republished with something that was missing
+	Revision    int       `json:"revision"`
+	Team        Team      `json:"team"`
+	Headline    string    `json:"headline"`
+	Link        string    `json:"link"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Yesterday   Yesterday `json:"yesterday"`
+	Upcoming    Upcoming  `json:"upcoming"`
+	// Standings is missing when they couldn't be fetched, and in the postseason
+	Standings *Standings `json:"standings,omitempty"`
+	// Postseason is only there in October, with every series of the current round
+	Postseason []Series `json:"postseason,omitempty"`
+	// Phase is offseason, springTraining, regularSeason, allStarBreak or postseason,
+	// missing when the season's dates couldn't be fetched
+	Phase string `json:"phase,omitempty"`
+	// Offseason counts down to spring training, once next season's dates are out
+	Offseason *Offseason `json:"offseason,omitempty"`
+	// Digest is the last week of moves in the offseason, grouped like signings or trades
+	Digest []MoveGroup `json:"digest,omitempty"`
+	// RosterMoves are the changes to the 40 man roster since the report before
+	RosterMoves []RosterMove `json:"rosterMoves,omitempty"`
+}
+
+type RosterMove struct {
+	PlayerId int    `json:"playerId"`
+	Name     string `json:"name"`
+	Position string `json:"position"`
+	Move     string `json:"move"`
+	Details  string `json:"details,omitempty"`
+}
+
+type MoveGroup struct {
+	Name  string `json:"name"`
+	Moves []Move `json:"moves"`
+}
+
+type Move struct {
+	// Date is yyyy-mm-dd
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+type Offseason struct {
+	Season          string `json:"season"`
+	DaysUntilSpring int    `json:"daysUntilSpring"`
+	// SpringStart is the day of the first spring training game, yyyy-mm-dd
+	SpringStart string `json:"springStart"`
+}
+
+type Team struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+type Yesterday struct {
+	// Games is empty on off days, and has more than one entry for doubleheaders
+	Games           []PastGame `json:"games"`
+	BaseballTheater string     `json:"baseballTheater"`
+}
+
+type PastGame struct {
+	// State is one of final, completedEarly, postponed, cancelled,
+	// suspended, delayed, inProgress or notStarted
+	State string `json:"state"`
+	// Reason is why the game was postponed, suspended, delayed, or called early
+	Reason         string `json:"reason,omitempty"`
+	Postponed      bool   `json:"postponed"`
+	PostponeReason string `json:"postponeReason,omitempty"`
+	// ResumesAt is set for suspended games, like "tomorrow at 12:05"
+	ResumesAt string `json:"resumesAt,omitempty"`
+	// ResumedFrom is set for games finished on a later day, like "May 27"
+	ResumedFrom string `json:"resumedFrom,omitempty"`
+	Venue       string `json:"venue"`
+	// IsWinnerHome is only ever true for a final (or completedEarly) game won by
+	// the home team. Before suspended, delayed and in progress games had a state
+	// it followed statsapi's isWinner, which can be set for the team ahead in a
+	// game that isn't over
+	IsWinnerHome bool `json:"isWinnerHome"`
+	// Winner and Loser are arbitrary for ties and games that were not decided,
+	// away then home
+	Winner           GameTeam   `json:"winner"`
+	Loser            GameTeam   `json:"loser"`
+	CondensedGameUrl string     `json:"condensedGameUrl,omitempty"`
+	Linescore        *Linescore `json:"linescore,omitempty"`
+	// Boxscore is only there when enabled with BOX_SCORE=true
+	Boxscore *Boxscore `json:"boxscore,omitempty"`
+	// ScoringPlays is empty for games that never started, or when the play by play couldn't be fetched
+	ScoringPlays []ScoringPlay `json:"scoringPlays"`
+}
+
+type ScoringPlay struct {
+	// Inning is like "Top 3rd"
+	Inning      string `json:"inning"`
+	Description string `json:"description"`
+	// Score is after the play, away team first
+	Score string `json:"score"`
+}
+
+type Boxscore struct {
+	// Starters is the away starter, then the home starter
+	Starters []PitchingLine `json:"starters"`
+	// Decisions are the winning, losing and save pitchers, in that order
+	Decisions  []PitchingLine `json:"decisions"`
+	TopHitters []BattingLine  `json:"topHitters"`
+}
+
+type PitchingLine struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	// Note is the decision, like (W, 5-2)
+	Note           string `json:"note,omitempty"`
+	InningsPitched string `json:"inningsPitched"`
+	Hits           int    `json:"hits"`
+	Runs           int    `json:"runs"`
+	EarnedRuns     int    `json:"earnedRuns"`
+	BaseOnBalls    int    `json:"baseOnBalls"`
+	StrikeOuts     int    `json:"strikeOuts"`
+}
+
+type BattingLine struct {
+	Name        string `json:"name"`
+	AtBats      int    `json:"atBats"`
+	Runs        int    `json:"runs"`
+	Hits        int    `json:"hits"`
+	HomeRuns    int    `json:"homeRuns"`
+	Rbi         int    `json:"rbi"`
+	BaseOnBalls int    `json:"baseOnBalls"`
+}
+
+type GameTeam struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+	Wins   int    `json:"wins"`
+	Losses int    `json:"losses"`
+}
+
+type Linescore struct {
+	Away LinescoreTeam `json:"away"`
+	Home LinescoreTeam `json:"home"`
+}
+
+type LinescoreTeam struct {
+	Abbreviation string `json:"abbreviation"`
+	// Innings holds runs per inning, null if the inning was not played (the x)
+	Innings []*int `json:"innings"`
+	Runs    int    `json:"runs"`
+	Hits    int    `json:"hits"`
+	Errors  int    `json:"errors"`
+}
+
+type Upcoming struct {
+	// Timezone is the abbreviation of the zone Time is in
+	Timezone string `json:"timezone"`
+	// Days is always 8 days long, starting with today
+	Days []Day `json:"days"`
+}
+
+type Day struct {
+	// Date is yyyy-mm-dd
+	Date    string       `json:"date"`
+	DayAbbr string       `json:"dayAbbr"`
+	Games   []FutureGame `json:"games"`
+}
+
+type FutureGame struct {
+	// Time is hh:mm
+	Time    string `json:"time"`
+	IsHome  bool   `json:"isHome"`
+	Against string `json:"against"`
+	// MyPitcher and TheirPitcher are the probable starters, once announced
+	MyPitcher    *ProbablePitcher `json:"myPitcher,omitempty"`
+	TheirPitcher *ProbablePitcher `json:"theirPitcher,omitempty"`
+}
+
+type ProbablePitcher struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// Wins, Losses and Era are this season's, left out when they couldn't be fetched
+	Wins   *int   `json:"wins,omitempty"`
+	Losses *int   `json:"losses,omitempty"`
+	Era    string `json:"era,omitempty"`
+}
+
+type Standings struct {
+	Division StandingsTable `json:"division"`
+	// WildCard is the top of the league's wild card race, plus the team if further behind
+	WildCard StandingsTable `json:"wildCard"`
+}
+
+type Series struct {
+	// Name is like ALDS, NL Wild Card or World Series
+	Name string `json:"name"`
+	// Teams has my team first when it is in the series, otherwise whoever is ahead
+	Teams []SeriesTeam `json:"teams"`
+	// BestOf is 0 when statsapi doesn't know yet, the series is never over then
+	BestOf int `json:"bestOf"`
+	// Status is like "Orioles lead 2-1"
+	Status string `json:"status"`
+	// NextGame is like "Game 4 tonight 19:08", missing once the series is over
+	NextGame string `json:"nextGame,omitempty"`
+	IsOver   bool   `json:"isOver"`
+}
+
+type SeriesTeam struct {
+	Id           int    `json:"id"`
+	Abbreviation string `json:"abbreviation"`
+	Name         string `json:"name"`
+	Wins         int    `json:"wins"`
+	IsMyTeam     bool   `json:"isMyTeam"`
+}
+
+type StandingsTable struct {
+	Name string         `json:"name"`
+	Rows []StandingsRow `json:"rows"`
+}
+
+type StandingsRow struct {
+	Abbreviation string `json:"abbreviation"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+	Pct          string `json:"pct"`
+	// GamesBack is "-" for the leader
+	GamesBack       string `json:"gamesBack"`
+	LastTen         string `json:"lastTen"`
+	Streak          string `json:"streak"`
+	RunDifferential int    `json:"runDifferential"`
+	IsMyTeam        bool   `json:"isMyTeam"`
+}
+
+// FromReport converts a report for the given team
+func FromReport(team mlb.Team, r report.Report) Report {
+	pastGames := make([]PastGame, 0, len(r.Yesterday.PastGames))
+	for _, pg := range r.Yesterday.PastGames {
+		var linescore *Linescore
+		if pg.HasLinescore {
+			linescore = &Linescore{
+				Away: fromLinescoreTeam(pg.Linescore.Away),
+				Home: fromLinescoreTeam(pg.Linescore.Home),
+			}
+		}
+
+		var boxscore *Boxscore
+		if pg.HasBoxscore {
+			boxscore = fromBoxscore(pg.Boxscore)
+		}
+
+		scoringPlays := make([]ScoringPlay, 0, len(pg.ScoringPlays))
+		for _, sp := range pg.ScoringPlays {
+			scoringPlays = append(scoringPlays, ScoringPlay{
+				Inning:      sp.Inning,
+				Description: sp.Description,
+				Score:       sp.Score,
+			})
+		}
+
+		state := string(pg.State)
+		if state == "" {
+			// reports saved before State existed
+			state = string(report.GameFinal)
+			if pg.PostponeReason != "" {
+				state = string(report.GamePostponed)
+			}
+		}
+
+		pastGames = append(pastGames, PastGame{
+			State:            state,
+			Reason:           pg.Reason,
+			Postponed:        pg.State == report.GamePostponed || pg.PostponeReason != "",
+			PostponeReason:   pg.PostponeReason,
+			ResumesAt:        pg.ResumesAt,
+			ResumedFrom:      pg.ResumedFrom,
+			Venue:            pg.Venue.Name,
+			IsWinnerHome:     pg.IsWinnerHome,
+			Winner:           fromGameTeam(pg.W),
+			Loser:            fromGameTeam(pg.L),
+			CondensedGameUrl: pg.CondensedGameUrl,
+			Linescore:        linescore,
+			Boxscore:         boxscore,
+			ScoringPlays:     scoringPlays,
+		})
+	}
+
+	days := make([]Day, 0, len(r.Upcoming.FutureDays))
+	for i, fd := range r.Upcoming.FutureDays {
+		games := make([]FutureGame, 0, len(fd.Games))
+		for _, fg := range fd.Games {
+			games = append(games, FutureGame{
+				Time:         fg.GameTimeLocal,
+				IsHome:       fg.IsMyTeamHome,
+				Against:      fg.AgainstAbbr,
+				MyPitcher:    fromProbablePitcher(fg.MyPitcher),
+				TheirPitcher: fromProbablePitcher(fg.TheirPitcher),
+			})
+		}
+
+		days = append(days, Day{
+			Date:    r.When.AddDate(0, 0, i).Format(time.DateOnly),
+			DayAbbr: fd.DayAbbr,
+			Games:   games,
+		})
+	}
+
+	var standings *Standings
+	if r.HasStandings {
+		standings = &Standings{
+			Division: fromStandingsTable(r.Standings.Division),
+			WildCard: fromStandingsTable(r.Standings.WildCard),
+		}
+	}
+
+	var postseason []Series
+	if r.HasPostseason {
+		postseason = fromPostseason(r.Postseason)
+	}
+
+	var offseason *Offseason
+	if r.HasOffseason {
+		offseason = &Offseason{
+			Season:          r.Offseason.Season,
+			DaysUntilSpring: r.Offseason.DaysUntilSpring,
+			SpringStart:     r.Offseason.SpringStartDate,
+		}
+	}
+
+	var digest []MoveGroup
+	if r.HasDigest {
+		digest = fromDigest(r.Digest)
+	}
+
+	var rosterMoves []RosterMove
+	if r.HasRosterMoves {
+		rosterMoves = make([]RosterMove, 0, len(r.RosterMoves))
+		for _, m := range r.RosterMoves {
+			rosterMoves = append(rosterMoves, RosterMove{
+				PlayerId: m.PlayerId,
+				Name:     m.Name,
+				Position: m.Position,
+				Move:     m.Move,
+				Details:  m.Details,
+			})
+		}
+	}
+
+	return Report{
+		SchemaVersion: SchemaVersion,
+		Id:            r.Key(),
+		Revision:      r.Revision,
+		Team: Team{
+			Id:           team.Id,
+			Name:         team.Name,
+			Abbreviation: team.Abbreviation,
+		},
+		Headline:    r.Headline,
+		Link:        r.Link,
+		GeneratedAt: r.When,
+		Yesterday: Yesterday{
+			Games:           pastGames,
+			BaseballTheater: r.Yesterday.BaseballTheater,
+		},
+		Upcoming: Upcoming{
+			Timezone: r.Upcoming.Timezone,
+			Days:     days,
+		},
+		Standings:   standings,
+		Postseason:  postseason,
+		Phase:       string(r.Phase),
+		Offseason:   offseason,
+		Digest:      digest,
+		RosterMoves: rosterMoves,
+	}
+}
+
+func fromGameTeam(gt mlb.GameTeam) GameTeam {
+	return GameTeam{
+		Id:     gt.Team.Id,
+		Name:   gt.Team.Name,
+		Score:  gt.Score,
+		Wins:   gt.LeagueRecord.Wins,
+		Losses: gt.LeagueRecord.Losses,
+	}
+}
+
+func fromLinescoreTeam(lt report.LinescoreTeam) LinescoreTeam {
+	innings := make([]*int, 0, len(lt.Innings))
+	for _, i := range lt.Innings {
+		i := i
+		if i < 0 {
+			innings = append(innings, nil)
+		} else {
+			innings = append(innings, &i)
+		}
+	}
+
+	return LinescoreTeam{
+		Abbreviation: lt.Abbr,
+		Innings:      innings,
+		Runs:         lt.Runs,
+		Hits:         lt.Hits,
+		Errors:       lt.Errors,
+	}
+}
+
+func fromStandingsTable(st report.StandingsTable) StandingsTable {
+	rows := make([]StandingsRow, 0, len(st.Rows))
+	for _, r := range st.Rows {
+		rows = append(rows, StandingsRow{
+			Abbreviation:    r.Abbr,
+			Wins:            r.Wins,
+			Losses:          r.Losses,
+			Pct:             r.Pct,
+			GamesBack:       r.GamesBack,
+			LastTen:         r.LastTen,
+			Streak:          r.Streak,
+			RunDifferential: r.RunDifferential,
+			IsMyTeam:        r.IsMyTeam,
+		})
+	}
+
+	return StandingsTable{
+		Name: st.Name,
+		Rows: rows,
+	}
+}
+
+func fromPostseason(p report.Postseason) []Series {
+	series := make([]Series, 0, len(p.Series))
+	for _, s := range p.Series {
+		teams := make([]SeriesTeam, 0, len(s.Teams))
+		for _, t := range s.Teams {
+			teams = append(teams, SeriesTeam{
+				Id:           t.Id,
+				Abbreviation: t.Abbr,
+				Name:         t.Name,
+				Wins:         t.Wins,
+				IsMyTeam:     t.IsMyTeam,
+			})
+		}
+
+		series = append(series, Series{
+			Name:     s.Name,
+			Teams:    teams,
+			BestOf:   s.BestOf,
+			Status:   s.Status,
+			NextGame: s.NextGame,
+			IsOver:   s.IsOver,
+		})
+	}
+
+	return series
+}
+
+func fromBoxscore(b report.Boxscore) *Boxscore {
+	pitchingLines := func(pls []report.PitchingLine) []PitchingLine {
+		lines := make([]PitchingLine, 0, len(pls))
+		for _, pl := range pls {
+			lines = append(lines, PitchingLine{
+				Name:           pl.Name,
+				Abbreviation:   pl.Abbr,
+				Note:           pl.Note,
+				InningsPitched: pl.InningsPitched,
+				Hits:           pl.Hits,
+				Runs:           pl.Runs,
+				EarnedRuns:     pl.EarnedRuns,
+				BaseOnBalls:    pl.BaseOnBalls,
+				StrikeOuts:     pl.StrikeOuts,
+			})
+		}
+		return lines
+	}
+
+	hitters := make([]BattingLine, 0, len(b.TopHitters))
+	for _, bl := range b.TopHitters {
+		hitters = append(hitters, BattingLine{
+			Name:        bl.Name,
+			AtBats:      bl.AtBats,
+			Runs:        bl.Runs,
+			Hits:        bl.Hits,
+			HomeRuns:    bl.HomeRuns,
+			Rbi:         bl.Rbi,
+			BaseOnBalls: bl.BaseOnBalls,
+		})
+	}
+
+	return &Boxscore{
+		Starters:   pitchingLines(b.Starters),
+		Decisions:  pitchingLines(b.Decisions),
+		TopHitters: hitters,
+	}
+}
+
+func fromDigest(d report.Digest) []MoveGroup {
+	groups := make([]MoveGroup, 0, len(d.Groups))
+	for _, g := range d.Groups {
+		moves := make([]Move, 0, len(g.Moves))
+		for _, m := range g.Moves {
+			moves = append(moves, Move{
+				Date:        m.Date,
+				Description: m.Description,
+			})
+		}
+
+		groups = append(groups, MoveGroup{
+			Name:  g.Name,
+			Moves: moves,
+		})
+	}
+
+	return groups
+}
+
+func fromProbablePitcher(p report.ProbablePitcher) *ProbablePitcher {
+	if p.Id == 0 {
+		return nil
+	}
+
+	pp := &ProbablePitcher{
+		Id:   p.Id,
+		Name: p.Name,
+	}
+	if p.HasStats {
+		pp.Wins = &p.Wins
+		pp.Losses = &p.Losses
+		pp.Era = p.Era
+	}
+
+	return pp
+}
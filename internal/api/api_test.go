@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/mlb/mlbtest"
+	"github.com/0queue/mlb-rss/internal/report"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+// scenarios are some of the report package's golden scenarios, the json of which
+// pins down the schema: a diff to these files should only ever add fields,
+// unless SchemaVersion goes up along with it
+var scenarios = []struct {
+	name     string
+	fixtures string
+	team     string
+	today    string
+}{
+	{name: "win", fixtures: "scenarios/win", team: "BAL", today: "2022-05-28"},
+	{name: "tie", fixtures: "scenarios/tie", team: "DET", today: "2023-03-20"},
+	{name: "postponed", fixtures: ".", team: "COL", today: "2022-05-28"},
+	{name: "suspended", fixtures: "scenarios/suspended", team: "BAL", today: "2022-05-28"},
+	{name: "postseason", fixtures: "scenarios/postseason", team: "BAL", today: "2023-10-08"},
+	{name: "offseason", fixtures: ".", team: "BAL", today: "2022-12-01"},
+	{name: "digest", fixtures: "scenarios/digest", team: "BAL", today: "2022-12-13"},
+}
+
+func TestGolden(t *testing.T) {
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) {
+			fixtures := os.DirFS(filepath.Join("..", "..", "test", "data", sc.fixtures))
+			mc, err := mlb.NewMlbClient(mlb.WithTransport(mlbtest.Replay(fixtures)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			team, ok := mc.FindTeam(sc.team)
+			if !ok {
+				t.Fatalf("no team %s", sc.team)
+			}
+
+			today, err := time.Parse(time.DateOnly, sc.today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			today = today.Add(7 * time.Hour)
+
+			rg := report.NewReportGenerator(team.Id, mc, time.UTC)
+			rg.BoxScore = true
+
+			r, err := rg.GenerateReport(today)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := json.MarshalIndent(FromReport(team, r), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, sc.name+".json", string(raw)+"\n")
+		})
+	}
+}
+
+// golden compares got to testdata/name, or overwrites it with -update
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+
+	p := filepath.Join("testdata", name)
+	if *update {
+		err := os.WriteFile(p, []byte(got), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("%v, run go test ./internal/api -update to create it", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("%s does not match, run go test ./internal/api -update and check the diff\n--- got\n%s", p, got)
+	}
+}
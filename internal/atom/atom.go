@@ -0,0 +1,48 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const Namespace = "http://www.w3.org/2005/Atom"
+
+type Feed struct {
+	XMLName  xml.Name `xml:"feed"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Id       string   `xml:"id"`
+	Title    string   `xml:"title"`
+	Subtitle string   `xml:"subtitle,omitempty"`
+	// time.Time already marshals as RFC 3339, which is what atom wants
+	Updated time.Time `xml:"updated"`
+	Links   []Link    `xml:"link"`
+	Author  *Person   `xml:"author"`
+	Entries []Entry   `xml:"entry"`
+}
+
+type Entry struct {
+	Id        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Updated   time.Time `xml:"updated"`
+	Published time.Time `xml:"published"`
+	Links     []Link    `xml:"link"`
+	Content   *Content  `xml:"content"`
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+	Uri  string `xml:"uri,omitempty"`
+}
+
+// Content with Type "html" holds escaped html, which is
+// what encoding/xml does to chardata anyway
+type Content struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
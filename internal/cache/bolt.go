@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OpenBolt opens (or creates) the bolt database at path,
+// which can then be shared by many BoltStores
+func OpenBolt(path string) (*bolt.DB, error) {
+	return bolt.Open(path, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+}
+
+// BoltStore keeps items as json in a single bucket of a bolt database
+type BoltStore[T any] struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+func NewBoltStore[T any](db *bolt.DB, bucket string) (*BoltStore[T], error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltStore[T]{
+		db:     db,
+		bucket: []byte(bucket),
+	}, nil
+}
+
+func (s *BoltStore[T]) Load() (map[string]T, error) {
+	items := make(map[string]T)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(k, v []byte) error {
+			var t T
+			err := json.Unmarshal(v, &t)
+			if err != nil {
+				return err
+			}
+
+			items[string(k)] = t
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (s *BoltStore[T]) Save(key string, t T) error {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), raw)
+	})
+}
+
+func (s *BoltStore[T]) Delete(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+}
@@ -1,25 +1,157 @@
 package cache
 
-import "sync"
+import (
+	"log/slog"
+	"sort"
+	"sync"
+)
 
 // just an excuse to play with generics
+//
+// Cache keeps the last few items, newest first. Items with the same key
+// replace each other instead of taking up another slot, so regenerating
+// today's report doesn't push yesterday's out.
 type Cache[T any] struct {
-	m    sync.Mutex
+	m     sync.Mutex
+	size  int
+	key   func(T) string
+	items []entry[T]
+	store Store[T]
+}
+
+type entry[T any] struct {
+	key  string
 	item T
-	ok   bool
 }
 
+// NewCache makes a cache that holds at most size items, identified by key.
+// Keys should sort in the same order the items were created, so that
+// a cache loaded from a Store comes back in the right order
+func NewCache[T any](size int, key func(T) string) *Cache[T] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &Cache[T]{
+		size: size,
+		key:  key,
+	}
+}
+
+// Persist loads the items already in s, and then writes
+// every following Set through to it
+func (c *Cache[T]) Persist(s Store[T]) error {
+	stored, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(stored))
+	for k := range stored {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.store = s
+	c.items = c.items[:0]
+	for i, k := range keys {
+		if i >= c.size {
+			c.deleteStored(k)
+			continue
+		}
+		c.items = append(c.items, entry[T]{key: k, item: stored[k]})
+	}
+
+	return nil
+}
+
+// Get returns the newest item
 func (c *Cache[T]) Get() (T, bool) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
-	return c.item, c.ok
+	if len(c.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return c.items[0].item, true
 }
 
+// Lookup finds the item with the given key
+func (c *Cache[T]) Lookup(key string) (T, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for _, e := range c.items {
+		if e.key == key {
+			return e.item, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// All returns every item, newest first
+func (c *Cache[T]) All() []T {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	all := make([]T, 0, len(c.items))
+	for _, e := range c.items {
+		all = append(all, e.item)
+	}
+
+	return all
+}
+
+// Set adds t, or replaces the item with the same key. Failing to
+// write to the Store is only logged, the cache itself is still updated
 func (c *Cache[T]) Set(t T) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
-	c.item = t
-	c.ok = true
-}
\ No newline at end of file
+	k := c.key(t)
+	c.save(k, t)
+
+	for i, e := range c.items {
+		if e.key == k {
+			c.items[i].item = t
+			return
+		}
+	}
+
+	c.items = append([]entry[T]{{key: k, item: t}}, c.items...)
+	if len(c.items) > c.size {
+		for _, e := range c.items[c.size:] {
+			c.deleteStored(e.key)
+		}
+		c.items = c.items[:c.size]
+	}
+}
+
+func (c *Cache[T]) save(k string, t T) {
+	if c.store == nil {
+		return
+	}
+
+	err := c.store.Save(k, t)
+	if err != nil {
+		slog.Warn("Failed to save cached item", slog.String("key", k), slog.String("err", err.Error()))
+	}
+}
+
+func (c *Cache[T]) deleteStored(k string) {
+	if c.store == nil {
+		return
+	}
+
+	err := c.store.Delete(k)
+	if err != nil {
+		slog.Warn("Failed to delete cached item", slog.String("key", k), slog.String("err", err.Error()))
+	}
+}
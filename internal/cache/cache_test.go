@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type item struct {
+	Key string
+	N   int
+}
+
+func itemKey(i item) string {
+	return i.Key
+}
+
+func TestJsonStore(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := NewJsonStore[item](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	// Save renames its temporary file over the item, nothing else is left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{"20230602.json"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got files %q, want %q", names, want)
+	}
+
+	// and a new store over the same directory sees the same items
+	s, err = NewJsonStore[item](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testReload(t, s)
+}
+
+func TestJsonStoreSkipsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := NewJsonStore[item](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// what a crash in the middle of Save leaves
+	err = os.WriteFile(filepath.Join(dir, "20230601.123.tmp"), []byte(`{"Key":`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("got %v, want nothing", items)
+	}
+}
+
+func TestBoltStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := OpenBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewBoltStore[item](db, "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	err = db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = OpenBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s, err = NewBoltStore[item](db, "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testReload(t, s)
+}
+
+// testStore leaves only 20230602 behind, for testReload
+func testStore(t *testing.T, s Store[item]) {
+	t.Helper()
+
+	for _, i := range []item{{"20230601", 1}, {"20230602", 2}, {"20230602", 3}} {
+		err := s.Save(i.Key, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]item{
+		"20230601": {"20230601", 1},
+		"20230602": {"20230602", 3},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %v, want %v", items, want)
+	}
+
+	err = s.Delete("20230601")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// deleting what isn't there is fine
+	err = s.Delete("20230601")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testReload(t *testing.T, s Store[item]) {
+	t.Helper()
+
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]item{"20230602": {"20230602", 3}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %v, want %v", items, want)
+	}
+}
+
+func TestPersist(t *testing.T) {
+	s, err := NewJsonStore[item](t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// saved out of order, Persist sorts them by key
+	for _, k := range []string{"20230602", "20230604", "20230601", "20230603"} {
+		err := s.Save(k, item{Key: k})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewCache(3, itemKey)
+	err = c.Persist(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := keys(c.All()), []string{"20230604", "20230603", "20230602"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the oldest didn't fit, and is gone from the store too
+	if got, want := stored(t, s), []string{"20230602", "20230603", "20230604"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got stored %q, want %q", got, want)
+	}
+
+	// replacing an item keeps its slot
+	c.Set(item{Key: "20230604", N: 1})
+	// and a new one pushes the oldest out
+	c.Set(item{Key: "20230605"})
+
+	if got, want := keys(c.All()), []string{"20230605", "20230604", "20230603"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := stored(t, s), []string{"20230603", "20230604", "20230605"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got stored %q, want %q", got, want)
+	}
+
+	// a restart picks up where it left off
+	c = NewCache(3, itemKey)
+	err = c.Persist(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newest, ok := c.Get()
+	if !ok || newest.Key != "20230605" {
+		t.Errorf("got newest %v, want 20230605", newest)
+	}
+	if i, ok := c.Lookup("20230604"); !ok || i.N != 1 {
+		t.Errorf("got %v, want the replaced 20230604", i)
+	}
+}
+
+func keys(items []item) []string {
+	var ks []string
+	for _, i := range items {
+		ks = append(ks, i.Key)
+	}
+	return ks
+}
+
+func stored(t *testing.T, s Store[item]) []string {
+	t.Helper()
+
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ks []string
+	for _, i := range items {
+		ks = append(ks, i.Key)
+	}
+	sort.Strings(ks)
+	return ks
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JsonStore keeps one json file per item in a directory
+type JsonStore[T any] struct {
+	dir string
+}
+
+func NewJsonStore[T any](dir string) (*JsonStore[T], error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JsonStore[T]{dir: dir}, nil
+}
+
+func (s *JsonStore[T]) Load() (map[string]T, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]T)
+	for _, e := range entries {
+		key, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var t T
+		err = json.Unmarshal(raw, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		items[key] = t
+	}
+
+	return items, nil
+}
+
+// Save writes to a temporary file first and renames it over the old one,
+// so a crash never leaves a half written item behind
+func (s *JsonStore[T]) Save(key string, t T) error {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(raw)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+func (s *JsonStore[T]) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *JsonStore[T]) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
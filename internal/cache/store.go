@@ -0,0 +1,11 @@
+package cache
+
+// Store persists cached items so they survive a restart
+type Store[T any] interface {
+	// Load returns every stored item by key
+	Load() (map[string]T, error)
+	// Save atomically writes t under key, replacing whatever was there
+	Save(key string, t T) error
+	// Delete removes the item under key, if any
+	Delete(key string) error
+}
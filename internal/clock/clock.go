@@ -0,0 +1,130 @@
+// Package clock is time.Now, but replaceable, so that code which
+// depends on what day it is can be run on any day
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	// After is time.After, on this clock
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the real time
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// OnDay is the real time of day, but always on the date of day.
+// Good for pretending it is a day covered by test data
+func OnDay(day time.Time) Clock {
+	return onDay{day: day}
+}
+
+type onDay struct {
+	day time.Time
+}
+
+func (c onDay) Now() time.Time {
+	now := time.Now()
+	y, m, d := c.day.Date()
+	return time.Date(y, m, d, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+}
+
+func (onDay) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake only moves when told to
+type Fake struct {
+	m       sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	until time.Time
+	c     chan time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.m)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return f.now
+}
+
+// After fires once the clock is moved to d from now
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+
+	f.waiters = append(f.waiters, waiter{until: f.now.Add(d), c: c})
+	f.changed.Broadcast()
+	return c
+}
+
+// BlockUntil waits for n calls to After that haven't fired yet,
+// so a test knows the code under test is waiting before moving the clock
+func (f *Fake) BlockUntil(n int) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
+
+// Set jumps to now
+func (f *Fake) Set(now time.Time) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.now = now
+	f.fire()
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.now = f.now.Add(d)
+	f.fire()
+}
+
+func (f *Fake) fire() {
+	waiters := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.until.After(f.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- f.now
+	}
+	f.waiters = waiters
+}
@@ -0,0 +1,38 @@
+package jsonfeed
+
+import "time"
+
+// Version is the JSON Feed version implemented here
+const Version = "https://jsonfeed.org/version/1.1"
+
+type Feed struct {
+	Version     string   `json:"version"`
+	Title       string   `json:"title"`
+	HomePageUrl string   `json:"home_page_url,omitempty"`
+	FeedUrl     string   `json:"feed_url,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Favicon     string   `json:"favicon,omitempty"`
+	Authors     []Author `json:"authors,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	// Items is required, so no omitempty
+	Items []Item `json:"items"`
+}
+
+type Item struct {
+	Id          string `json:"id"`
+	Url         string `json:"url,omitempty"`
+	Title       string `json:"title,omitempty"`
+	ContentHtml string `json:"content_html,omitempty"`
+	ContentText string `json:"content_text,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	// time.Time already marshals as RFC 3339
+	DatePublished *time.Time `json:"date_published,omitempty"`
+	DateModified  *time.Time `json:"date_modified,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+}
+
+type Author struct {
+	Name string `json:"name,omitempty"`
+	Url  string `json:"url,omitempty"`
+}
@@ -0,0 +1,250 @@
+// Package live watches the games of a team while they are being played,
+// and turns the notable moments into feed items
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// Source is what watching a game takes from statsapi, mlb.MlbClient is the real thing
+type Source interface {
+	FetchSchedule(start, end time.Time, teamId int) (mlb.Schedule, error)
+	FetchLinescore(gamePk int) (mlb.Linescore, error)
+	Team(id int) (mlb.Team, bool)
+}
+
+var _ Source = (*mlb.MlbClient)(nil)
+
+// Event is a notable moment in a game
+type Event struct {
+	// Key is stable no matter how often or when the game is polled,
+	// for example 718780-lead-4-bottom
+	Key     string
+	GamePk  int
+	Title   string
+	Summary string
+	Link    string
+	// When is the poll that first saw the event, statsapi doesn't say when a
+	// half inning ended. The start is the scheduled first pitch instead
+	When time.Time
+}
+
+// Events derives every event so far from the state of the game and its linescore.
+// Lead changes are only as precise as the half inning they happened in
+func Events(g mlb.Game, l mlb.Linescore, teams map[int]mlb.Team, now time.Time) []Event {
+	if g.Status.AbstractGameState == "Preview" || isPostponed(g) {
+		return nil
+	}
+
+	home := teams[g.Teams.Home.Team.Id].TeamName
+	away := teams[g.Teams.Away.Team.Id].TeamName
+	link := fmt.Sprintf("https://www.mlb.com/gameday/%d", g.GamePk)
+	prefix := strconv.Itoa(g.GamePk)
+
+	started := now
+	if !g.GameDate.IsZero() && g.GameDate.Before(now) {
+		started = g.GameDate
+	}
+
+	events := []Event{
+		{
+			Key:     prefix + "-start",
+			GamePk:  g.GamePk,
+			Title:   fmt.Sprintf("%s @ %s has started", away, home),
+			Summary: fmt.Sprintf("First pitch at %s", g.Venue.Name),
+			Link:    link,
+			When:    started,
+		},
+	}
+
+	// walk the innings half by half, keeping track of who's ahead
+	var homeRuns, awayRuns int
+	var leader string
+	lead := func(inning int, half string) {
+		var current, title string
+		switch {
+		case homeRuns > awayRuns:
+			current = home
+			title = fmt.Sprintf("%s take the lead %d-%d", home, homeRuns, awayRuns)
+		case awayRuns > homeRuns:
+			current = away
+			title = fmt.Sprintf("%s take the lead %d-%d", away, awayRuns, homeRuns)
+		default:
+			return
+		}
+
+		if current == leader {
+			return
+		}
+		leader = current
+
+		events = append(events, Event{
+			Key:     fmt.Sprintf("%s-lead-%d-%s", prefix, inning, half),
+			GamePk:  g.GamePk,
+			Title:   title,
+			Summary: fmt.Sprintf("After the %s of the %s: %s %d, %s %d", half, mlb.Ordinal(inning), away, awayRuns, home, homeRuns),
+			Link:    link,
+			When:    now,
+		})
+	}
+
+	for _, i := range l.Innings {
+		awayRuns += i.Away.Runs
+		lead(i.Num, "top")
+		homeRuns += i.Home.Runs
+		lead(i.Num, "bottom")
+	}
+
+	if isOver(g) {
+		// the schedule has the score, even when the linescore is lagging behind
+		winner, loser := g.Teams.Home, g.Teams.Away
+		if g.Teams.Away.Score > g.Teams.Home.Score {
+			winner, loser = loser, winner
+		}
+
+		events = append(events, Event{
+			Key:    prefix + "-final",
+			GamePk: g.GamePk,
+			Title: fmt.Sprintf("Final: %s %d, %s %d",
+				teams[winner.Team.Id].TeamName, winner.Score,
+				teams[loser.Team.Id].TeamName, loser.Score,
+			),
+			Summary: fmt.Sprintf("%s @ %s is over after %d innings", away, home, len(l.Innings)),
+			Link:    link,
+			When:    now,
+		})
+	}
+
+	return events
+}
+
+// Watch polls every game of the team today, and keeps doing so every day until ctx is done.
+// onEvent is called with every event, including ones it has been called with before
+func Watch(ctx context.Context, src Source, clk clock.Clock, teamId int, poll time.Duration, onEvent func(Event)) {
+	go func() {
+		for {
+			today := clk.Now()
+			watchDay(ctx, src, clk, teamId, today, poll, onEvent)
+
+			// start again tomorrow morning
+			y, m, d := today.AddDate(0, 0, 1).Date()
+			tomorrow := time.Date(y, m, d, 0, 5, 0, 0, today.Location())
+			if !sleep(ctx, clk, tomorrow.Sub(clk.Now())) {
+				return
+			}
+		}
+	}()
+}
+
+func watchDay(ctx context.Context, src Source, clk clock.Clock, teamId int, day time.Time, poll time.Duration, onEvent func(Event)) {
+	s, err := src.FetchSchedule(day, day, teamId)
+	if err != nil {
+		slog.Error("Failed to fetch live schedule", slog.String("err", err.Error()))
+		return
+	}
+
+	var games []mlb.Game
+	for _, d := range s.Dates {
+		games = append(games, d.Games...)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].GameDate.Before(games[j].GameDate)
+	})
+
+	for _, g := range games {
+		if !sleep(ctx, clk, g.GameDate.Sub(clk.Now())) {
+			return
+		}
+
+		slog.Info("Watching live game", slog.Int("gamePk", g.GamePk))
+		watchGame(ctx, src, clk, teamId, day, g, poll, onEvent)
+	}
+}
+
+// watchGame polls until the game is final
+func watchGame(ctx context.Context, src Source, clk clock.Clock, teamId int, day time.Time, g mlb.Game, poll time.Duration, onEvent func(Event)) {
+	for {
+		current, err := fetchGame(src, teamId, day, g.GamePk)
+		if err != nil {
+			slog.Warn("Failed to fetch live game", slog.Int("gamePk", g.GamePk), slog.String("err", err.Error()))
+		} else if current.Status.AbstractGameState != "Preview" {
+			g = current
+
+			l, err := src.FetchLinescore(g.GamePk)
+			if err != nil {
+				slog.Warn("Failed to fetch live linescore", slog.Int("gamePk", g.GamePk), slog.String("err", err.Error()))
+			} else {
+				for _, e := range Events(g, l, gameTeams(src, g), clk.Now()) {
+					onEvent(e)
+				}
+			}
+
+			if g.Status.AbstractGameState == "Final" {
+				return
+			}
+		}
+
+		if !sleep(ctx, clk, poll) {
+			return
+		}
+	}
+}
+
+// gameTeams are the two teams of g, by id
+func gameTeams(src Source, g mlb.Game) map[int]mlb.Team {
+	teams := make(map[int]mlb.Team)
+	for _, id := range []int{g.Teams.Home.Team.Id, g.Teams.Away.Team.Id} {
+		teams[id], _ = src.Team(id)
+	}
+	return teams
+}
+
+// fetchGame gets the current state of the game from the schedule of the day
+// it was scheduled on, which isn't always the day of GameDate in UTC
+func fetchGame(src Source, teamId int, day time.Time, gamePk int) (mlb.Game, error) {
+	s, err := src.FetchSchedule(day, day, teamId)
+	if err != nil {
+		return mlb.Game{}, err
+	}
+
+	for _, d := range s.Dates {
+		for _, g := range d.Games {
+			if g.GamePk == gamePk {
+				return g, nil
+			}
+		}
+	}
+
+	return mlb.Game{}, fmt.Errorf("game %d not in schedule", gamePk)
+}
+
+// sleep returns false if ctx was done first
+func sleep(ctx context.Context, clk clock.Clock, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-clk.After(d):
+		return true
+	}
+}
+
+func isPostponed(g mlb.Game) bool {
+	return g.Status.CodedGameState == "D" || g.Status.CodedGameState == "C"
+}
+
+// isOver is true for games that actually finished, unlike postponed or suspended ones
+func isOver(g mlb.Game) bool {
+	return g.Status.CodedGameState == "F" || g.Status.CodedGameState == "O"
+}
@@ -0,0 +1,314 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+const (
+	bal = 110
+	nyy = 147
+)
+
+var teams = map[int]mlb.Team{
+	bal: {Id: bal, TeamName: "Orioles"},
+	nyy: {Id: nyy, TeamName: "Yankees"},
+}
+
+// poll is the state of the game at one poll, innings are {away, home} runs
+type poll struct {
+	state   string
+	innings [][2]int
+}
+
+func TestEvents(t *testing.T) {
+	firstPitch := time.Date(2023, 6, 1, 23, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		polls []poll
+		// wantKeys is what the last poll derives, every poll before it must be a prefix
+		wantKeys []string
+		// wantTitles are the titles of the events after the start
+		wantTitles []string
+	}{
+		{
+			name: "not started",
+			polls: []poll{
+				{state: "S"},
+			},
+		},
+		{
+			name: "start",
+			polls: []poll{
+				{state: "I"},
+				{state: "I", innings: [][2]int{{0, 0}}},
+			},
+			wantKeys: []string{"1-start"},
+		},
+		{
+			name: "tie",
+			polls: []poll{
+				{state: "I", innings: [][2]int{{1, 0}}},
+				{state: "I", innings: [][2]int{{1, 1}}},
+				{state: "I", innings: [][2]int{{1, 1}, {0, 0}}},
+			},
+			// the tie takes the lead away, but isn't an event of its own
+			wantKeys:   []string{"1-start", "1-lead-1-top"},
+			wantTitles: []string{"Yankees take the lead 1-0"},
+		},
+		{
+			name: "lead swap",
+			polls: []poll{
+				{state: "I", innings: [][2]int{{1, 0}}},
+				{state: "I", innings: [][2]int{{1, 2}}},
+				{state: "I", innings: [][2]int{{1, 2}, {2, 0}}},
+			},
+			wantKeys:   []string{"1-start", "1-lead-1-top", "1-lead-1-bottom", "1-lead-2-top"},
+			wantTitles: []string{"Yankees take the lead 1-0", "Orioles take the lead 2-1", "Yankees take the lead 3-2"},
+		},
+		{
+			name: "retaking the lead after a tie",
+			polls: []poll{
+				{state: "I", innings: [][2]int{{1, 0}}},
+				{state: "I", innings: [][2]int{{1, 1}, {0, 0}, {1, 0}}},
+			},
+			// the same team going ahead again after a tie isn't a lead change
+			wantKeys:   []string{"1-start", "1-lead-1-top"},
+			wantTitles: []string{"Yankees take the lead 1-0"},
+		},
+		{
+			name: "final",
+			polls: []poll{
+				{state: "I", innings: [][2]int{{0, 1}}},
+				{state: "F", innings: [][2]int{{0, 1}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}}},
+			},
+			wantKeys:   []string{"1-start", "1-lead-1-bottom", "1-final"},
+			wantTitles: []string{"Orioles take the lead 1-0", "Final: Orioles 1, Yankees 0"},
+		},
+		{
+			name: "postponed",
+			polls: []poll{
+				{state: "D"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []Event
+			for i, p := range tt.polls {
+				g, l := game(firstPitch, p)
+				now := firstPitch.Add(time.Duration(i+1) * time.Hour)
+
+				events = Events(g, l, teams, now)
+
+				// polling the same state again, later, derives the same keys
+				again := Events(g, l, teams, now.Add(time.Minute))
+				if got, want := keys(again), keys(events); !reflect.DeepEqual(got, want) {
+					t.Errorf("poll %d: got keys %q polling again, want %q", i, got, want)
+				}
+
+				// and nothing seen before changes key
+				if i < len(tt.polls)-1 {
+					final := tt.wantKeys
+					if got := keys(events); len(got) > len(final) || !reflect.DeepEqual(got, final[:len(got)]) {
+						t.Errorf("poll %d: got keys %q, want a prefix of %q", i, got, final)
+					}
+				}
+			}
+
+			if got := keys(events); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("got keys %q, want %q", got, tt.wantKeys)
+			}
+
+			var titles []string
+			for _, e := range events {
+				if e.Key != "1-start" {
+					titles = append(titles, e.Title)
+				}
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("got titles %q, want %q", titles, tt.wantTitles)
+			}
+
+			if len(events) > 0 && !events[0].When.Equal(firstPitch) {
+				t.Errorf("got start at %v, want the first pitch %v", events[0].When, firstPitch)
+			}
+		})
+	}
+}
+
+func game(firstPitch time.Time, p poll) (mlb.Game, mlb.Linescore) {
+	g := mlb.Game{
+		GamePk:   1,
+		GameDate: firstPitch,
+	}
+	g.Teams.Away.Team.Id = nyy
+	g.Teams.Home.Team.Id = bal
+	g.Status.CodedGameState = p.state
+	switch p.state {
+	case "S", "P":
+		g.Status.AbstractGameState = "Preview"
+	case "F", "O":
+		g.Status.AbstractGameState = "Final"
+	default:
+		g.Status.AbstractGameState = "Live"
+	}
+
+	var l mlb.Linescore
+	for i, runs := range p.innings {
+		l.Innings = append(l.Innings, mlb.Inning{
+			Num:  i + 1,
+			Away: mlb.Stats{Runs: runs[0]},
+			Home: mlb.Stats{Runs: runs[1]},
+		})
+		g.Teams.Away.Score += runs[0]
+		g.Teams.Home.Score += runs[1]
+	}
+
+	return g, l
+}
+
+func keys(events []Event) []string {
+	var ks []string
+	for _, e := range events {
+		ks = append(ks, e.Key)
+	}
+	return ks
+}
+
+// source is a schedule that changes as the test goes, one game per day at most
+type source struct {
+	m          sync.Mutex
+	games      map[string]mlb.Game
+	linescores map[int]mlb.Linescore
+	// fetched are the days the schedule was fetched for
+	fetched []string
+}
+
+func (s *source) set(day string, g mlb.Game, l mlb.Linescore) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.games[day] = g
+	s.linescores[g.GamePk] = l
+}
+
+func (s *source) FetchSchedule(start, end time.Time, teamId int) (mlb.Schedule, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	day := start.Format(time.DateOnly)
+	s.fetched = append(s.fetched, day)
+
+	g, ok := s.games[day]
+	if !ok {
+		return mlb.Schedule{}, nil
+	}
+	return mlb.Schedule{Dates: []mlb.Date{{Date: day, Games: []mlb.Game{g}}}}, nil
+}
+
+func (s *source) FetchLinescore(gamePk int) (mlb.Linescore, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	l, ok := s.linescores[gamePk]
+	if !ok {
+		return mlb.Linescore{}, errors.New("no linescore")
+	}
+	return l, nil
+}
+
+func (s *source) Team(id int) (mlb.Team, bool) {
+	t, ok := teams[id]
+	return t, ok
+}
+
+func (s *source) days() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]string(nil), s.fetched...)
+}
+
+// receive waits for the next n events
+func receive(t *testing.T, events <-chan Event, n int) []string {
+	t.Helper()
+
+	var ks []string
+	for len(ks) < n {
+		select {
+		case e := <-events:
+			ks = append(ks, e.Key)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got events %q, want %d", ks, n)
+		}
+	}
+	return ks
+}
+
+func TestWatch(t *testing.T) {
+	firstPitch := time.Date(2023, 6, 1, 17, 5, 0, 0, time.UTC)
+	clk := clock.NewFake(time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC))
+
+	src := &source{
+		games:      make(map[string]mlb.Game),
+		linescores: make(map[int]mlb.Linescore),
+	}
+	g, l := game(firstPitch, poll{state: "S"})
+	src.set("2023-06-01", g, l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan Event, 10)
+	Watch(ctx, src, clk, bal, time.Minute, func(e Event) { events <- e })
+
+	// waiting for first pitch, by the clock it was given
+	clk.BlockUntil(1)
+	if got, want := src.days(), []string{"2023-06-01"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got schedules for %q, want %q", got, want)
+	}
+
+	// a delay keeps it in the pregame state past the scheduled time
+	clk.Set(firstPitch)
+	clk.BlockUntil(1)
+
+	g, l = game(firstPitch, poll{state: "I", innings: [][2]int{{1, 0}}})
+	src.set("2023-06-01", g, l)
+	clk.Advance(time.Minute)
+	if got, want := receive(t, events, 2), []string{"1-start", "1-lead-1-top"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// every poll comes with every event so far, AddEvent sorts out the new ones
+	g, l = game(firstPitch, poll{state: "F", innings: [][2]int{{1, 0}, {0, 0}}})
+	src.set("2023-06-01", g, l)
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	if got, want := receive(t, events, 3), []string{"1-start", "1-lead-1-top", "1-final"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// done for the day, until tomorrow morning
+	clk.BlockUntil(1)
+	clk.Set(time.Date(2023, 6, 2, 0, 5, 0, 0, time.UTC))
+	clk.BlockUntil(1)
+	if got, want := src.days(), []string{"2023-06-01", "2023-06-01", "2023-06-01", "2023-06-01", "2023-06-02"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got schedules for %q, want %q", got, want)
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("got %q on a day without games", e.Key)
+	default:
+	}
+}
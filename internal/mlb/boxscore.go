@@ -0,0 +1,63 @@
+package mlb
+
+import "strconv"
+
+type Boxscore struct {
+	Teams struct {
+		Away BoxscoreTeam
+		Home BoxscoreTeam
+	}
+}
+
+type BoxscoreTeam struct {
+	Team TeamSummary
+	// keyed by "ID" + person id
+	Players map[string]BoxscorePlayer
+	// person ids, in the order they appeared in the game
+	Batters  []int
+	Pitchers []int
+}
+
+type BoxscorePlayer struct {
+	Person   Person
+	Position Position
+	Stats    struct {
+		Batting  BattingStats
+		Pitching PitchingStats
+	}
+}
+
+type Person struct {
+	Id       int
+	FullName string
+}
+
+type Position struct {
+	Abbreviation string
+}
+
+type BattingStats struct {
+	AtBats      int
+	Runs        int
+	Hits        int
+	HomeRuns    int
+	Rbi         int
+	BaseOnBalls int
+	StrikeOuts  int
+}
+
+type PitchingStats struct {
+	InningsPitched string
+	Hits           int
+	Runs           int
+	EarnedRuns     int
+	BaseOnBalls    int
+	StrikeOuts     int
+	// decisions show up here, like (W, 5-2), (L, 1-3) or (S, 12)
+	Note string
+}
+
+func (bt *BoxscoreTeam) FindPlayer(id int) (BoxscorePlayer, bool) {
+	p, ok := bt.Players["ID"+strconv.Itoa(id)]
+	return p, ok
+}
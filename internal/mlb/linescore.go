@@ -1,5 +1,7 @@
 package mlb
 
+import "fmt"
+
 type Linescore struct {
 	// if this is true, then the game ended with the home team
 	// not playing the last inning because they won already
@@ -23,3 +25,19 @@ type Stats struct {
 	Errors     int
 	LeftOnBase int
 }
+
+// Ordinal is like 1st, 2nd, 3rd or 11th, for innings
+func Ordinal(i int) string {
+	switch {
+	case i%100 >= 11 && i%100 <= 13:
+		return fmt.Sprintf("%dth", i)
+	case i%10 == 1:
+		return fmt.Sprintf("%dst", i)
+	case i%10 == 2:
+		return fmt.Sprintf("%dnd", i)
+	case i%10 == 3:
+		return fmt.Sprintf("%drd", i)
+	default:
+		return fmt.Sprintf("%dth", i)
+	}
+}
@@ -1,9 +1,10 @@
 package mlb
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
-	"io"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -11,19 +12,60 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
 )
 
-var apiEndpoint = "https://statsapi.mlb.com/api/v1"
+const DefaultBaseUrl = "https://statsapi.mlb.com/api/v1"
 
 //go:embed teams.json
 var teamInfoEmbed []byte
 
 type MlbClient struct {
 	AllTeams map[int]Team
+	baseUrl  string
 	client   http.Client
+	breaker  breaker
+	// ctx cuts retries short, and clock times the backoff and the breaker
+	ctx   context.Context
+	clock clock.Clock
+}
+
+type Option func(mc *MlbClient)
+
+// WithBaseUrl points the client at something other than statsapi.mlb.com,
+// like mlbtest's fake server. An empty url keeps the default
+func WithBaseUrl(baseUrl string) Option {
+	return func(mc *MlbClient) {
+		if baseUrl != "" {
+			mc.baseUrl = baseUrl
+		}
+	}
 }
 
-func NewMlbClient() (*MlbClient, error) {
+// WithTransport replaces the http transport, to record or replay responses for example
+func WithTransport(rt http.RoundTripper) Option {
+	return func(mc *MlbClient) {
+		mc.client.Transport = rt
+	}
+}
+
+// WithContext stops retrying requests once ctx is done, for a quick shutdown
+func WithContext(ctx context.Context) Option {
+	return func(mc *MlbClient) {
+		mc.ctx = ctx
+	}
+}
+
+// WithClock replaces the clock the retry backoff and the circuit breaker run on
+func WithClock(clk clock.Clock) Option {
+	return func(mc *MlbClient) {
+		mc.clock = clk
+	}
+}
+
+// LoadTeams is all the teams mlb-rss knows about, by id
+func LoadTeams() (map[int]Team, error) {
 	var teamFullSlice struct {
 		Teams []Team
 	}
@@ -38,24 +80,41 @@ func NewMlbClient() (*MlbClient, error) {
 		teams[t.Id] = t
 	}
 
+	return teams, nil
+}
+
+func NewMlbClient(opts ...Option) (*MlbClient, error) {
+	teams, err := LoadTeams()
+	if err != nil {
+		return nil, err
+	}
+
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
 
-	return &MlbClient{
+	mc := &MlbClient{
 		AllTeams: teams,
+		baseUrl:  DefaultBaseUrl,
 		client:   client,
-	}, nil
+		ctx:      context.Background(),
+		clock:    clock.System,
+	}
+	for _, opt := range opts {
+		opt(mc)
+	}
+
+	return mc, nil
 }
 
 // Download raw json
-// mostly used to fetch test data
+// test data is best fetched with mlb-rss record
 // if start (date) == end (date), only fetches data for that day
 func (mc *MlbClient) FetchScheduleRaw(start, end time.Time, teamId int) ([]byte, error) {
 	startDate := start.Format(time.DateOnly)
 	endDate := end.Format(time.DateOnly)
 
-	u, err := url.Parse(apiEndpoint)
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -67,68 +126,164 @@ func (mc *MlbClient) FetchScheduleRaw(start, end time.Time, teamId int) ([]byte,
 	q.Set("teamId", strconv.Itoa(teamId))
 	q.Set("startDate", startDate)
 	q.Set("endDate", endDate)
+	// the season line of the probable pitchers comes along, instead of a request per pitcher
+	q.Set("hydrate", "probablePitcher(stats(group=[pitching],type=[season]))")
 	u.RawQuery = q.Encode()
 
 	slog.Info("Fetching raw schedule", slog.String("url", u.String()))
 
-	resp, err := mc.client.Get(u.String())
+	return mc.get(u)
+}
+
+// PostseasonGameTypes are wild card, division series, league championship series and world series
+const PostseasonGameTypes = "F,D,L,W"
+
+// FetchPostseasonScheduleRaw is every postseason game of the season, for every team
+func (mc *MlbClient) FetchPostseasonScheduleRaw(season int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	u.Path = path.Join(u.Path, "schedule")
+
+	q := u.Query()
+	q.Set("sportId", "1")
+	q.Set("season", strconv.Itoa(season))
+	q.Set("gameType", PostseasonGameTypes)
+	u.RawQuery = q.Encode()
+
+	slog.Info("Fetching raw postseason schedule", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
+
+func (mc *MlbClient) FetchContentRaw(gamePk int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "content")
+
+	return mc.get(u)
 }
 
-func (mc *MlbClient) FetchContentRaw(gamePk int) ([]byte, error) {
-	u, err := url.Parse(apiEndpoint)
+func (mc *MlbClient) FetchLinescoreRaw(gamePk int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "content")
+	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "linescore")
+
+	slog.Info("Fetching raw linescore", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
 
-	resp, err := mc.client.Get(u.String())
+func (mc *MlbClient) FetchBoxscoreRaw(gamePk int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "boxscore")
+
+	slog.Info("Fetching raw boxscore", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
+
+func (mc *MlbClient) FetchPlayByPlayRaw(gamePk int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "playByPlay")
+
+	slog.Info("Fetching raw play by play", slog.String("url", u.String()))
+
+	return mc.get(u)
 }
 
-func (mc *MlbClient) FetchLinescoreRaw(gamePk int) ([]byte, error) {
-	u, err := url.Parse(apiEndpoint)
+// standingsType is regularSeason, wildCard, etc
+func (mc *MlbClient) FetchStandingsRaw(leagueId, season int, standingsType string) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	u.Path = path.Join(u.Path, "game", strconv.Itoa(gamePk), "linescore")
+	u.Path = path.Join(u.Path, "standings")
 
-	slog.Info("Fetching raw linescore", slog.String("url", u.String()))
+	q := u.Query()
+	q.Set("leagueId", strconv.Itoa(leagueId))
+	q.Set("season", strconv.Itoa(season))
+	q.Set("standingsTypes", standingsType)
+	u.RawQuery = q.Encode()
+
+	slog.Info("Fetching raw standings", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
 
-	resp, err := mc.client.Get(u.String())
+// FetchSeasonRaw is the calendar of the season, from spring training to the world series
+func (mc *MlbClient) FetchSeasonRaw(season int) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	u.Path = path.Join(u.Path, "seasons", strconv.Itoa(season))
+
+	q := u.Query()
+	q.Set("sportId", "1")
+	u.RawQuery = q.Encode()
+
+	slog.Info("Fetching raw season", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
+
+// FetchTransactionsRaw is every signing, trade, option and so on of the team
+// from start to end, including the ones of its minor league affiliates
+func (mc *MlbClient) FetchTransactionsRaw(teamId int, start, end time.Time) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	return body, nil
+	u.Path = path.Join(u.Path, "transactions")
+
+	q := u.Query()
+	q.Set("teamId", strconv.Itoa(teamId))
+	q.Set("startDate", start.Format(time.DateOnly))
+	q.Set("endDate", end.Format(time.DateOnly))
+	u.RawQuery = q.Encode()
+
+	slog.Info("Fetching raw transactions", slog.String("url", u.String()))
+
+	return mc.get(u)
+}
+
+// FetchRosterRaw is the 40 man roster of the team on the day of date
+func (mc *MlbClient) FetchRosterRaw(teamId int, date time.Time) ([]byte, error) {
+	u, err := url.Parse(mc.baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Path = path.Join(u.Path, "teams", strconv.Itoa(teamId), "roster")
+
+	q := u.Query()
+	q.Set("rosterType", RosterType40Man)
+	q.Set("date", date.Format(time.DateOnly))
+	u.RawQuery = q.Encode()
+
+	slog.Info("Fetching raw roster", slog.String("url", u.String()))
+
+	return mc.get(u)
 }
 
 func (mc *MlbClient) FetchContent(gamePk int) (Content, error) {
@@ -161,6 +316,21 @@ func (mc *MlbClient) FetchSchedule(start, end time.Time, teamId int) (Schedule,
 	return s, nil
 }
 
+func (mc *MlbClient) FetchPostseasonSchedule(season int) (Schedule, error) {
+	raw, err := mc.FetchPostseasonScheduleRaw(season)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	var s Schedule
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return s, nil
+}
+
 func (mc *MlbClient) FetchLinescore(gamePk int) (Linescore, error) {
 	raw, err := mc.FetchLinescoreRaw(gamePk)
 	if err != nil {
@@ -176,31 +346,136 @@ func (mc *MlbClient) FetchLinescore(gamePk int) (Linescore, error) {
 	return l, nil
 }
 
+func (mc *MlbClient) FetchBoxscore(gamePk int) (Boxscore, error) {
+	raw, err := mc.FetchBoxscoreRaw(gamePk)
+	if err != nil {
+		return Boxscore{}, err
+	}
+
+	var b Boxscore
+	err = json.Unmarshal(raw, &b)
+	if err != nil {
+		return Boxscore{}, err
+	}
+
+	return b, nil
+}
+
+func (mc *MlbClient) FetchPlayByPlay(gamePk int) (PlayByPlay, error) {
+	raw, err := mc.FetchPlayByPlayRaw(gamePk)
+	if err != nil {
+		return PlayByPlay{}, err
+	}
+
+	var p PlayByPlay
+	err = json.Unmarshal(raw, &p)
+	if err != nil {
+		return PlayByPlay{}, err
+	}
+
+	return p, nil
+}
+
+func (mc *MlbClient) FetchStandings(leagueId, season int, standingsType string) (Standings, error) {
+	raw, err := mc.FetchStandingsRaw(leagueId, season, standingsType)
+	if err != nil {
+		return Standings{}, err
+	}
+
+	var s Standings
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return Standings{}, err
+	}
+
+	return s, nil
+}
+
+// FetchSeason fails when the season isn't known yet, which is
+// usually the case for next season until the schedule is out
+func (mc *MlbClient) FetchSeason(season int) (Season, error) {
+	raw, err := mc.FetchSeasonRaw(season)
+	if err != nil {
+		return Season{}, err
+	}
+
+	var s Seasons
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return Season{}, err
+	}
+
+	if len(s.Seasons) == 0 {
+		return Season{}, fmt.Errorf("no season %d", season)
+	}
+
+	return s.Seasons[0], nil
+}
+
+func (mc *MlbClient) FetchTransactions(teamId int, start, end time.Time) (Transactions, error) {
+	raw, err := mc.FetchTransactionsRaw(teamId, start, end)
+	if err != nil {
+		return Transactions{}, err
+	}
+
+	var t Transactions
+	err = json.Unmarshal(raw, &t)
+	if err != nil {
+		return Transactions{}, err
+	}
+
+	return t, nil
+}
+
+func (mc *MlbClient) FetchRoster(teamId int, date time.Time) (Roster, error) {
+	raw, err := mc.FetchRosterRaw(teamId, date)
+	if err != nil {
+		return Roster{}, err
+	}
+
+	var r Roster
+	err = json.Unmarshal(raw, &r)
+	if err != nil {
+		return Roster{}, err
+	}
+
+	return r, nil
+}
+
 // TODO find out where I got the data, and make a function to download it
 // https://statsapi.mlb.com/api/v1/teams?sportId=1
 func (mc *MlbClient) FetchTeamFull() {
 	panic("not implemented yet")
 }
 
-// FindTeam searches for a team based on the abbreviation if q is three letters,
+// Team looks up a team by id
+func (mc *MlbClient) Team(id int) (Team, bool) {
+	t, ok := mc.AllTeams[id]
+	return t, ok
+}
+
+// FindTeam searches for a team based on the abbreviation,
 // or as a substring of the full name
 func (mc *MlbClient) FindTeam(q string) (Team, bool) {
-	var found Team
-	var ok bool
+	if q == "" {
+		return Team{}, false
+	}
+
+	// abbreviations first, otherwise "SEA" or "SD" could match some other
+	// team's name depending on map iteration order
 	for _, t := range mc.AllTeams {
-		t := t
-		if len(q) == 3 && strings.ToLower(t.Abbreviation) == strings.ToLower(q) {
-			found = t
-			ok = true
-			break
-		} else if strings.Contains(strings.ToLower(t.Name), strings.ToLower(q)) {
-			found = t
-			ok = true
-			break
+		if strings.EqualFold(t.Abbreviation, q) {
+			return t, true
+		}
+	}
+
+	for _, t := range mc.AllTeams {
+		if strings.Contains(strings.ToLower(t.Name), strings.ToLower(q)) {
+			return t, true
 		}
 	}
 
-	return found, ok
+	return Team{}, false
 }
 
 // search for typ=mlbtax and value=condensed_game
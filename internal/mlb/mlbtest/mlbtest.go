@@ -0,0 +1,188 @@
+// Package mlbtest is a fake statsapi that serves json fixtures out of a directory,
+// so the whole pipeline can run without a network.
+//
+// Every request is first looked up by FixturePath, which is also how recorded
+// fixtures are named. For example
+//
+//	game/718780/linescore.json
+//	standings/leagueId=103&season=2023&standingsTypes=wildCard.json
+//
+// Schedule requests without an exact fixture fall back to the schedule
+// responses at the top level of the directory (like test/data/games.json),
+// filtered down to the requested dates and team.
+package mlbtest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefix is where the fake api lives, like the real one
+const Prefix = "/api/v1"
+
+// FixturePath is where the response for u is stored, relative to the fixture directory
+func FixturePath(u *url.URL) string {
+	p := strings.TrimPrefix(path.Clean(u.Path), Prefix)
+	p = strings.TrimPrefix(p, "/")
+
+	// Encode sorts by key, so the same query always maps to the same file
+	if q := u.Query(); len(q) > 0 {
+		p = path.Join(p, q.Encode())
+	}
+
+	return p + ".json"
+}
+
+// Handler serves the fixtures in fsys under Prefix
+func Handler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, Prefix+"/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fixture := FixturePath(r.URL)
+		raw, err := fs.ReadFile(fsys, fixture)
+		if errors.Is(err, fs.ErrNotExist) && strings.TrimPrefix(r.URL.Path, Prefix) == "/schedule" {
+			raw, err = filterSchedules(fsys, r.URL.Query())
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("Missing fixture", slog.String("fixture", fixture))
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to read fixture", slog.String("fixture", fixture), slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(raw)
+	})
+}
+
+// NewServer starts a fake statsapi, point an MlbClient at it with mlb.WithBaseUrl(BaseUrl(s))
+func NewServer(fsys fs.FS) *httptest.Server {
+	return httptest.NewServer(Handler(fsys))
+}
+
+// BaseUrl is the url to give to mlb.WithBaseUrl
+func BaseUrl(s *httptest.Server) string {
+	return s.URL + Prefix
+}
+
+// the least of a schedule needed to filter it, while keeping every game as is
+type rawSchedule struct {
+	Dates []rawDate `json:"dates"`
+}
+
+type rawDate struct {
+	Date  string            `json:"date"`
+	Games []json.RawMessage `json:"games"`
+}
+
+type rawGame struct {
+	GamePk   int
+	GameType string
+	Teams    struct {
+		Away struct{ Team struct{ Id int } }
+		Home struct{ Team struct{ Id int } }
+	}
+}
+
+// filterSchedules merges every top level schedule fixture, keeping only the
+// dates between startDate and endDate, the games of teamId, and the games
+// of the comma separated gameType, if given
+func filterSchedules(fsys fs.FS, q url.Values) ([]byte, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fs.ErrNotExist
+	}
+
+	startDate := q.Get("startDate")
+	endDate := q.Get("endDate")
+	teamId, _ := strconv.Atoi(q.Get("teamId"))
+	var gameTypes []string
+	if q.Get("gameType") != "" {
+		gameTypes = strings.Split(q.Get("gameType"), ",")
+	}
+
+	dates := make(map[string][]json.RawMessage)
+	seen := make(map[int]bool)
+	for _, name := range names {
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var s rawSchedule
+		err = json.Unmarshal(raw, &s)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range s.Dates {
+			// yyyy-mm-dd compares just fine as a string
+			if (startDate != "" && d.Date < startDate) || (endDate != "" && d.Date > endDate) {
+				continue
+			}
+
+			for _, g := range d.Games {
+				var game rawGame
+				err = json.Unmarshal(g, &game)
+				if err != nil {
+					return nil, err
+				}
+
+				if seen[game.GamePk] {
+					continue
+				}
+				if teamId != 0 && game.Teams.Home.Team.Id != teamId && game.Teams.Away.Team.Id != teamId {
+					continue
+				}
+				if gameTypes != nil && !contains(gameTypes, game.GameType) {
+					continue
+				}
+
+				seen[game.GamePk] = true
+				dates[d.Date] = append(dates[d.Date], g)
+			}
+		}
+	}
+
+	// like the real thing, dates without games are left out
+	filtered := rawSchedule{Dates: []rawDate{}}
+	for date, games := range dates {
+		filtered.Dates = append(filtered.Dates, rawDate{
+			Date:  date,
+			Games: games,
+		})
+	}
+	sort.Slice(filtered.Dates, func(i, j int) bool {
+		return filtered.Dates[i].Date < filtered.Dates[j].Date
+	})
+
+	return json.Marshal(filtered)
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
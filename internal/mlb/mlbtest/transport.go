@@ -0,0 +1,64 @@
+package mlbtest
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Record saves every successful response that passes through next into dir,
+// named by FixturePath, so that Replay or the fake server can serve them later
+func Record(dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return resp, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		fixture := filepath.Join(dir, filepath.FromSlash(FixturePath(req.URL)))
+		err = os.MkdirAll(filepath.Dir(fixture), 0o755)
+		if err == nil {
+			err = os.WriteFile(fixture, body, 0o644)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		slog.Info("Recorded fixture", slog.String("fixture", fixture))
+		return resp, nil
+	})
+}
+
+// Replay answers every request from the fixtures in fsys, without touching the network
+func Replay(fsys fs.FS) http.RoundTripper {
+	h := Handler(fsys)
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
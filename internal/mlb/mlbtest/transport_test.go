@@ -0,0 +1,98 @@
+package mlbtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	statsapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Prefix+"/game/2/linescore" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// every response is different, to tell which one got recorded
+		io.WriteString(w, `{"path":"`+r.URL.Path+`","query":"`+r.URL.RawQuery+`"}`)
+	}))
+	defer statsapi.Close()
+
+	dir := t.TempDir()
+	record := &http.Client{Transport: Record(dir, nil)}
+
+	get := func(c *http.Client, url string) (int, string) {
+		t.Helper()
+
+		resp, err := c.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	base := statsapi.URL + Prefix
+	recorded := make(map[string]string)
+	for _, u := range []string{
+		base + "/game/1/linescore",
+		base + "/standings?season=2023&leagueId=103",
+		// failures are not worth keeping
+		base + "/game/2/linescore",
+	} {
+		status, body := get(record, u)
+		if status == http.StatusOK {
+			recorded[u] = body
+		}
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, p)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	want := []string{
+		"game/1/linescore.json",
+		// the query is sorted by key
+		"standings/leagueId=103&season=2023.json",
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("got fixtures %q, want %q", files, want)
+	}
+
+	replay := &http.Client{Transport: Replay(os.DirFS(dir))}
+	for u, body := range recorded {
+		status, got := get(replay, u)
+		if status != http.StatusOK || got != body {
+			t.Errorf("replaying %s got %d %s, want %s", u, status, got, body)
+		}
+	}
+
+	// the same query in any order is the same fixture
+	status, got := get(replay, base+"/standings?leagueId=103&season=2023")
+	if want := recorded[base+"/standings?season=2023&leagueId=103"]; status != http.StatusOK || got != want {
+		t.Errorf("got %d %s for the reordered query, want %s", status, got, want)
+	}
+
+	// and without a fixture there is nothing to replay
+	if status, _ := get(replay, base+"/game/2/linescore"); status != http.StatusNotFound {
+		t.Errorf("got %d for a missing fixture, want 404", status)
+	}
+}
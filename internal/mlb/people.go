@@ -0,0 +1,45 @@
+package mlb
+
+// PersonStats are the stats hydrated into a person, one entry
+// for every stats type and group that was asked for
+type PersonStats struct {
+	Stats []struct {
+		Splits []StatsSplit
+	}
+}
+
+type StatsSplit struct {
+	Season string
+	Stat   SeasonPitchingStats
+}
+
+// SeasonPitchingStats are a pitcher's totals for the season
+type SeasonPitchingStats struct {
+	Wins           int
+	Losses         int
+	GamesStarted   int
+	InningsPitched string
+	// Era is like 3.12, or -.-- before the first out
+	Era string
+}
+
+// ProbablePitcher is an announced starter, along with their
+// season pitching line (see the hydrate in FetchScheduleRaw)
+type ProbablePitcher struct {
+	Person
+	PersonStats
+}
+
+// SeasonPitching is the pitching line for season, which
+// isn't there for a pitcher who hasn't pitched in it yet
+func (p ProbablePitcher) SeasonPitching(season string) (SeasonPitchingStats, bool) {
+	for _, s := range p.Stats {
+		for _, split := range s.Splits {
+			if split.Season == season {
+				return split.Stat, true
+			}
+		}
+	}
+
+	return SeasonPitchingStats{}, false
+}
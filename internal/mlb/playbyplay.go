@@ -0,0 +1,42 @@
+package mlb
+
+type PlayByPlay struct {
+	AllPlays []Play
+	// indexes into AllPlays
+	ScoringPlays []int
+}
+
+type Play struct {
+	Result PlayResult
+	About  PlayAbout
+}
+
+type PlayResult struct {
+	Type        string
+	Event       string
+	Description string
+	Rbi         int
+	// the score after the play
+	AwayScore int
+	HomeScore int
+}
+
+type PlayAbout struct {
+	AtBatIndex int
+	// top or bottom
+	HalfInning    string
+	Inning        int
+	IsScoringPlay bool
+}
+
+// FindScoringPlays returns the plays that scored at least one run, in order
+func (p *PlayByPlay) FindScoringPlays() []Play {
+	plays := make([]Play, 0, len(p.ScoringPlays))
+	for _, i := range p.ScoringPlays {
+		if i >= 0 && i < len(p.AllPlays) {
+			plays = append(plays, p.AllPlays[i])
+		}
+	}
+
+	return plays
+}
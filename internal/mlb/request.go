@@ -0,0 +1,144 @@
+package mlb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAttempts includes the first try
+	maxAttempts = 4
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 8 * time.Second
+
+	// after this many requests fail in a row, stop making requests for breakerCooldown
+	breakerThreshold = 3
+	breakerCooldown  = time.Minute
+)
+
+// ErrCircuitOpen is returned without making a request while statsapi looks to be down
+var ErrCircuitOpen = errors.New("statsapi circuit breaker is open")
+
+// StatusError is returned for any non 2xx response
+type StatusError struct {
+	StatusCode int
+	Url        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("statsapi responded %d for %s", e.StatusCode, e.Url)
+}
+
+// retryable is true for errors that might go away by trying again. Only a
+// 4xx (besides 429) means statsapi is up, anything else like a timeout, a
+// refused connection or a response cut short could be an outage
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+
+	return true
+}
+
+// breaker is a circuit breaker, which opens after breakerThreshold failures in a row
+type breaker struct {
+	m         sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow is false while the breaker is open. Once the cooldown is over
+// requests are let through again, and the first failure reopens it
+func (b *breaker) allow(now time.Time) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return !now.Before(b.openUntil)
+}
+
+func (b *breaker) record(err error, now time.Time) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	// a 404 means statsapi is up, just the thing isn't there
+	if err == nil || !retryable(err) {
+		b.failures = 0
+		return
+	}
+
+	b.failures += 1
+	if b.failures >= breakerThreshold {
+		slog.Warn("Opening statsapi circuit breaker", slog.Int("failures", b.failures))
+		b.openUntil = now.Add(breakerCooldown)
+	}
+}
+
+// get is what every Fetch*Raw is built on, retrying with exponential
+// backoff on server errors and network errors. Retries stop early once
+// the client's context is done
+func (mc *MlbClient) get(u *url.URL) ([]byte, error) {
+	if !mc.breaker.allow(mc.clock.Now()) {
+		return nil, ErrCircuitOpen
+	}
+
+	var body []byte
+	var err error
+	backoff := baseBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt += 1 {
+		body, err = mc.getOnce(u)
+		if err == nil || !retryable(err) || attempt == maxAttempts {
+			break
+		}
+
+		slog.Warn("Retrying statsapi request",
+			slog.String("url", u.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.String("err", err.Error()),
+		)
+
+		select {
+		case <-mc.ctx.Done():
+			mc.breaker.record(err, mc.clock.Now())
+			return nil, err
+		case <-mc.clock.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	mc.breaker.record(err, mc.clock.Now())
+
+	return body, err
+}
+
+func (mc *MlbClient) getOnce(u *url.URL) ([]byte, error) {
+	resp, err := mc.client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Url:        u.String(),
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,168 @@
+package mlb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// instantClock doesn't wait for anything, it just remembers what it was asked to wait for
+type instantClock struct {
+	m     sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *instantClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.slept = append(c.slept, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *instantClock) advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// statusServer responds with each of statuses in turn, then 200
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int) {
+	var m sync.Mutex
+	requests := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		status := http.StatusOK
+		if requests < len(statuses) {
+			status = statuses[requests]
+		}
+		requests += 1
+
+		w.WriteHeader(status)
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(s.Close)
+
+	return s, &requests
+}
+
+func newTestClient(t *testing.T, s *httptest.Server) (*MlbClient, *instantClock) {
+	clk := &instantClock{now: time.Date(2023, 6, 1, 7, 0, 0, 0, time.UTC)}
+	mc, err := NewMlbClient(WithBaseUrl(s.URL), WithClock(clk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mc, clk
+}
+
+func mustParse(t *testing.T, s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	s, requests := statusServer(t, 503, 503)
+	mc, clk := newTestClient(t, s)
+
+	_, err := mc.get(mustParse(t, s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *requests != 3 {
+		t.Errorf("made %d requests, want 3", *requests)
+	}
+	if len(clk.slept) != 2 || clk.slept[0] != baseBackoff || clk.slept[1] != 2*baseBackoff {
+		t.Errorf("backed off %v", clk.slept)
+	}
+}
+
+func TestNoRetryNotFound(t *testing.T) {
+	s, requests := statusServer(t, 404)
+	mc, _ := newTestClient(t, s)
+
+	_, err := mc.get(mustParse(t, s.URL))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Fatalf("got %v, want a 404", err)
+	}
+
+	if *requests != 1 {
+		t.Errorf("made %d requests, want 1", *requests)
+	}
+}
+
+func TestRetryUnreachable(t *testing.T) {
+	s, _ := statusServer(t)
+	mc, clk := newTestClient(t, s)
+	u := mustParse(t, s.URL)
+	// nothing listens there anymore, so the connection is refused
+	s.Close()
+
+	_, err := mc.get(u)
+	if err == nil {
+		t.Fatal("got no error from a closed server")
+	}
+
+	if len(clk.slept) != maxAttempts-1 {
+		t.Errorf("retried %d times, want %d", len(clk.slept), maxAttempts-1)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	// every attempt of every request fails
+	var statuses []int
+	for i := 0; i < breakerThreshold*maxAttempts; i++ {
+		statuses = append(statuses, 503)
+	}
+	s, requests := statusServer(t, statuses...)
+	mc, clk := newTestClient(t, s)
+	u := mustParse(t, s.URL)
+
+	for i := 0; i < breakerThreshold; i++ {
+		_, err := mc.get(u)
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != 503 {
+			t.Fatalf("request %d got %v, want a 503", i, err)
+		}
+	}
+
+	made := *requests
+	_, err := mc.get(u)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+	if *requests != made {
+		t.Errorf("made a request while the breaker was open")
+	}
+
+	clk.advance(breakerCooldown - time.Second)
+	if _, err := mc.get(u); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v during the cooldown, want ErrCircuitOpen", err)
+	}
+
+	// statsapi is back by the end of the cooldown
+	clk.advance(time.Second)
+	if _, err := mc.get(u); err != nil {
+		t.Fatalf("got %v after the cooldown", err)
+	}
+}
@@ -0,0 +1,23 @@
+package mlb
+
+// RosterType40Man includes the injured list and the players optioned to
+// the minors, unlike the active roster
+const RosterType40Man = "40Man"
+
+type Roster struct {
+	Roster []RosterEntry
+}
+
+type RosterEntry struct {
+	Person       Person
+	JerseyNumber string
+	Position     Position
+	Status       RosterStatus
+}
+
+type RosterStatus struct {
+	// Code is like A (active), D15 (15-day injured list) or MIN (in the minors)
+	Code string
+	// Description is like Active or Injured 15-Day
+	Description string
+}
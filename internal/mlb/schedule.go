@@ -27,13 +27,30 @@ type Game struct {
 	GamesInSeries     int
 	SeriesGameNumber  int
 	SeriesDescription string
+	// ResumeDate is when a suspended game will be picked back up
+	ResumeDate time.Time
+	// ResumedFrom is when a resumed game was originally played
+	ResumedFrom time.Time
+}
+
+// IsPostseason is true for wild card, division series,
+// league championship series and world series games
+func (g Game) IsPostseason() bool {
+	switch g.GameType {
+	case "F", "D", "L", "W":
+		return true
+	default:
+		return false
+	}
 }
 
 type Status struct {
-	CodedGameState string
-	DetailedState  string
-	StartTimeTBD   bool
-	Reason         string
+	// Preview, Live or Final
+	AbstractGameState string
+	CodedGameState    string
+	DetailedState     string
+	StartTimeTBD      bool
+	Reason            string
 }
 
 type Teams struct {
@@ -47,6 +64,10 @@ type GameTeam struct {
 	SeriesNumber int
 	Score        int
 	IsWinner     bool
+	// SplitSquad is for spring training, when a team plays more than one game at once
+	SplitSquad bool
+	// ProbablePitcher is the announced starter, Id is 0 until there is one
+	ProbablePitcher ProbablePitcher
 }
 
 type LeagueRecord struct {
@@ -77,4 +98,18 @@ type Team struct {
 	TeamName     string
 	LocationName string
 	ShortName    string
+	League       League
+	Division     Division
+}
+
+type League struct {
+	Id   int
+	Name string
+	Link string
+}
+
+type Division struct {
+	Id   int
+	Name string
+	Link string
 }
@@ -0,0 +1,53 @@
+package mlb
+
+import "time"
+
+type Seasons struct {
+	Seasons []Season
+}
+
+// Season is the calendar of one season, every date is yyyy-mm-dd
+type Season struct {
+	SeasonId               string
+	SpringStartDate        string
+	SpringEndDate          string
+	RegularSeasonStartDate string
+	// LastDate1stHalf is the last day before the All-Star break
+	LastDate1stHalf      string
+	AllStarDate          string
+	FirstDate2ndHalf     string
+	RegularSeasonEndDate string
+	PostSeasonStartDate  string
+	PostSeasonEndDate    string
+}
+
+type Phase string
+
+const (
+	PhaseOffseason      Phase = "offseason"
+	PhaseSpringTraining Phase = "springTraining"
+	PhaseRegularSeason  Phase = "regularSeason"
+	PhaseAllStarBreak   Phase = "allStarBreak"
+	PhasePostseason     Phase = "postseason"
+)
+
+// Phase is the part of the season day is in. The few days between spring
+// training and opening day count as spring training, and the days between the
+// end of the regular season and the first postseason game as the postseason
+func (s Season) Phase(day time.Time) Phase {
+	// yyyy-mm-dd compares just fine as a string
+	d := day.Format(time.DateOnly)
+
+	switch {
+	case d < s.SpringStartDate || d > s.PostSeasonEndDate:
+		return PhaseOffseason
+	case d < s.RegularSeasonStartDate:
+		return PhaseSpringTraining
+	case d > s.LastDate1stHalf && d < s.FirstDate2ndHalf:
+		return PhaseAllStarBreak
+	case d > s.RegularSeasonEndDate:
+		return PhasePostseason
+	default:
+		return PhaseRegularSeason
+	}
+}
@@ -0,0 +1,52 @@
+package mlb
+
+type Standings struct {
+	Records []StandingsRecord
+}
+
+// StandingsRecord is one division, or one league for the wild card standings
+type StandingsRecord struct {
+	StandingsType string
+	League        League
+	Division      Division
+	TeamRecords   []TeamRecord
+}
+
+type TeamRecord struct {
+	Team              TeamSummary
+	Streak            Streak
+	DivisionRank      string
+	LeagueRank        string
+	WildCardRank      string
+	GamesBack         string
+	WildCardGamesBack string
+	LeagueRecord      LeagueRecord
+	RunDifferential   int
+	Records           struct {
+		SplitRecords []SplitRecord
+	}
+}
+
+type Streak struct {
+	// W3, L1, etc
+	StreakCode string
+}
+
+type SplitRecord struct {
+	// home, away, lastTen, etc
+	Type   string
+	Wins   int
+	Losses int
+	Pct    string
+}
+
+// FindSplitRecord searches for a split such as "lastTen"
+func (tr *TeamRecord) FindSplitRecord(typ string) (SplitRecord, bool) {
+	for _, sr := range tr.Records.SplitRecords {
+		if sr.Type == typ {
+			return sr, true
+		}
+	}
+
+	return SplitRecord{}, false
+}
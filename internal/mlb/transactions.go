@@ -0,0 +1,39 @@
+package mlb
+
+type Transactions struct {
+	Transactions []Transaction
+}
+
+// Transaction type codes, there are plenty more like ASG (assigned) or NUM (number change)
+const (
+	TransactionSignedFreeAgent = "SFA"
+	TransactionSigned          = "SGN"
+	TransactionTrade           = "TR"
+	// TransactionDesignated is designated for assignment, DFA for short
+	TransactionDesignated = "DES"
+	TransactionOptioned   = "OPT"
+	TransactionRecalled   = "CU"
+	// TransactionSelected is a minor leaguer's contract being selected to the 40 man roster
+	TransactionSelected = "SE"
+	// TransactionClaimed is claimed off waivers
+	TransactionClaimed    = "CLW"
+	TransactionOutrighted = "OUT"
+	TransactionReleased   = "REL"
+	// TransactionStatusChange covers the injured list, among other things
+	TransactionStatusChange = "SC"
+)
+
+type Transaction struct {
+	Id       int
+	Person   Person
+	FromTeam TeamSummary
+	ToTeam   TeamSummary
+	// Date is yyyy-mm-dd
+	Date string
+	// TypeCode is one of the Transaction constants, or one of the many others
+	TypeCode string
+	// TypeDesc is like "Signed as Free Agent"
+	TypeDesc string
+	// Description is a whole sentence, like "Baltimore Orioles signed free agent RHP Kyle Gibson."
+	Description string
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// SeasonSource is the part of Source a Calendar needs
+type SeasonSource interface {
+	FetchSeason(season int) (mlb.Season, error)
+}
+
+// Calendar knows what part of the season a day is in. Seasons are only
+// fetched once, their dates don't move around once the schedule is out
+type Calendar struct {
+	src     SeasonSource
+	m       sync.Mutex
+	seasons map[int]mlb.Season
+}
+
+func NewCalendar(src SeasonSource) *Calendar {
+	return &Calendar{
+		src:     src,
+		seasons: make(map[int]mlb.Season),
+	}
+}
+
+// Phase is the part of the season today is in, along with the season it
+// belongs to. Once the postseason is over the offseason belongs to next
+// season, so there is a spring training to count down to, if it is known yet
+func (c *Calendar) Phase(today time.Time) (mlb.Phase, mlb.Season, error) {
+	s, err := c.season(today.Year())
+	if err != nil {
+		return "", mlb.Season{}, err
+	}
+
+	phase := s.Phase(today)
+	if phase == mlb.PhaseOffseason && today.Format(time.DateOnly) > s.PostSeasonEndDate {
+		if next, err := c.season(today.Year() + 1); err == nil {
+			s = next
+		}
+	}
+
+	return phase, s, nil
+}
+
+func (c *Calendar) season(year int) (mlb.Season, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if s, ok := c.seasons[year]; ok {
+		return s, nil
+	}
+
+	s, err := c.src.FetchSeason(year)
+	if err != nil {
+		return mlb.Season{}, err
+	}
+
+	c.seasons[year] = s
+	return s, nil
+}
+
+// offseason counts down to spring training, once next season is known
+func (rg *ReportGenerator) offseason(phase mlb.Phase, season mlb.Season, today time.Time) (Offseason, bool) {
+	if phase != mlb.PhaseOffseason {
+		return Offseason{}, false
+	}
+
+	spring, err := time.Parse(time.DateOnly, season.SpringStartDate)
+	if err != nil {
+		return Offseason{}, false
+	}
+
+	// whole days, without the time of day or daylight saving time getting in the way
+	y, m, d := today.In(rg.Location).Date()
+	days := int(spring.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	if days <= 0 {
+		return Offseason{}, false
+	}
+
+	return Offseason{
+		Season:          season.SeasonId,
+		DaysUntilSpring: days,
+		SpringStart:     spring.Format("January 2"),
+		SpringStartDate: season.SpringStartDate,
+	}, true
+}
+
+// seasonDate turns a yyyy-mm-dd date of the season into something like July 14
+func (rg *ReportGenerator) seasonDate(date string) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	return t.Format("January 2")
+}
+
+func (o Offseason) headline() string {
+	if o.DaysUntilSpring == 1 {
+		return "Spring training starts tomorrow!"
+	}
+	return fmt.Sprintf("%d days until spring training", o.DaysUntilSpring)
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// digestDays is how far back the digest goes, the offseason only gets a report once a week
+const digestDays = 7
+
+// digestGroups are the moves worth reading about, in the order they are shown
+var digestGroups = []struct {
+	name      string
+	typeCodes []string
+}{
+	{"Signings", []string{mlb.TransactionSignedFreeAgent, mlb.TransactionSigned}},
+	{"Trades", []string{mlb.TransactionTrade}},
+	{"Designated for assignment", []string{mlb.TransactionDesignated}},
+	{"Options", []string{mlb.TransactionOptioned, mlb.TransactionRecalled}},
+}
+
+// fetchDigest groups the last week of my team's moves. Moves that only
+// shuffle players around the minor league affiliates are left out
+func (rg *ReportGenerator) fetchDigest(today time.Time) (Digest, error) {
+	ts, err := rg.src.FetchTransactions(rg.MyTeamId, today.AddDate(0, 0, -digestDays), today.AddDate(0, 0, -1))
+	if err != nil {
+		return Digest{}, err
+	}
+
+	transactions := ts.Transactions
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date < transactions[j].Date
+	})
+
+	groups := make([]MoveGroup, 0)
+	for _, dg := range digestGroups {
+		group := MoveGroup{Name: dg.name}
+		// a trade shows up once for every player in it, with the same description
+		seen := make(map[string]bool)
+		for _, t := range transactions {
+			if t.ToTeam.Id != rg.MyTeamId && t.FromTeam.Id != rg.MyTeamId {
+				continue
+			}
+			if !contains(dg.typeCodes, t.TypeCode) || seen[t.Description] {
+				continue
+			}
+			seen[t.Description] = true
+
+			day := t.Date
+			if d, err := time.Parse(time.DateOnly, t.Date); err == nil {
+				day = d.Format("Jan 2")
+			}
+
+			group.Moves = append(group.Moves, Move{
+				Day:         day,
+				Date:        t.Date,
+				Description: t.Description,
+			})
+		}
+
+		if len(group.Moves) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	return Digest{Groups: groups}, nil
+}
+
+// Moves counts the moves of every group
+func (d Digest) Moves() int {
+	moves := 0
+	for _, g := range d.Groups {
+		moves += len(g.Moves)
+	}
+	return moves
+}
+
+func (d Digest) headline() string {
+	if d.Moves() == 1 {
+		return "1 move this week"
+	}
+	return fmt.Sprintf("%d moves this week", d.Moves())
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+const (
+	bal = 110
+	nyy = 147
+	bos = 111
+)
+
+func finalGame(gamePk, awayId, awayScore, homeId, homeScore int) mlb.Game {
+	g := mlb.Game{
+		GamePk: gamePk,
+		Status: mlb.Status{AbstractGameState: "Final", CodedGameState: "F", DetailedState: "Final"},
+	}
+	g.Teams.Away.Team.Id = awayId
+	g.Teams.Away.Score = awayScore
+	g.Teams.Away.IsWinner = awayScore > homeScore
+	g.Teams.Home.Team.Id = homeId
+	g.Teams.Home.Score = homeScore
+	g.Teams.Home.IsWinner = homeScore > awayScore
+	return g
+}
+
+func postponedGame(gamePk, awayId, homeId int, reason string) mlb.Game {
+	g := mlb.Game{
+		GamePk: gamePk,
+		Status: mlb.Status{AbstractGameState: "Final", CodedGameState: "D", DetailedState: "Postponed", Reason: reason},
+	}
+	g.Teams.Away.Team.Id = awayId
+	g.Teams.Home.Team.Id = homeId
+	return g
+}
+
+func withStatus(g mlb.Game, codedGameState, detailedState string) mlb.Game {
+	g.Status.CodedGameState = codedGameState
+	g.Status.DetailedState = detailedState
+	return g
+}
+
+func resumedGame(g mlb.Game, from time.Time) mlb.Game {
+	g.ResumedFrom = from
+	return g
+}
+
+func suspendedGame(g mlb.Game, resume time.Time) mlb.Game {
+	g = withStatus(g, "T", "Suspended: Rain")
+	g.Teams.Away.IsWinner = false
+	g.Teams.Home.IsWinner = false
+	g.ResumeDate = resume
+	return g
+}
+
+func splitSquadGame(g mlb.Game) mlb.Game {
+	g.Teams.Away.SplitSquad = g.Teams.Away.Team.Id == bal
+	g.Teams.Home.SplitSquad = g.Teams.Home.Team.Id == bal
+	return g
+}
+
+func TestHeadline(t *testing.T) {
+	tests := []struct {
+		name  string
+		games []mlb.Game
+		want  string
+	}{
+		{
+			name: "off day",
+			want: "Baseball Report Sunday 2022-05-29",
+		},
+		{
+			name:  "win",
+			games: []mlb.Game{finalGame(1, bal, 5, nyy, 2)},
+			want:  "The Baltimore Orioles win! 5 to 2",
+		},
+		{
+			name:  "loss",
+			games: []mlb.Game{finalGame(1, nyy, 7, bal, 1)},
+			want:  "The Baltimore Orioles lose, 1 to 7",
+		},
+		{
+			name:  "postponed",
+			games: []mlb.Game{postponedGame(1, nyy, bal, "Rain")},
+			want:  "Game was postponed due to Rain",
+		},
+		{
+			name:  "cancelled",
+			games: []mlb.Game{withStatus(postponedGame(1, nyy, bal, ""), "C", "Cancelled")},
+			want:  "Game was cancelled",
+		},
+		{
+			name:  "suspended",
+			games: []mlb.Game{suspendedGame(finalGame(1, nyy, 3, bal, 2), time.Date(2022, 5, 30, 16, 5, 0, 0, time.UTC))},
+			want:  "Game suspended due to Rain, resumes tomorrow at 16:05",
+		},
+		{
+			name:  "suspended without a date",
+			games: []mlb.Game{suspendedGame(finalGame(1, nyy, 3, bal, 2), time.Time{})},
+			want:  "Game was suspended due to Rain",
+		},
+		{
+			name:  "completed early",
+			games: []mlb.Game{withStatus(finalGame(1, nyy, 1, bal, 4), "F", "Completed Early: Rain")},
+			want:  "The Baltimore Orioles win! 4 to 1, called early due to Rain",
+		},
+		{
+			name:  "resumed",
+			games: []mlb.Game{resumedGame(finalGame(1, nyy, 6, bal, 5), time.Date(2022, 5, 26, 23, 5, 0, 0, time.UTC))},
+			want:  "The Baltimore Orioles lose, 5 to 6 in a game resumed from May 26",
+		},
+		{
+			name:  "delayed",
+			games: []mlb.Game{withStatus(finalGame(1, nyy, 0, bal, 0), "I", "Delayed: Rain")},
+			want:  "Game is delayed due to Rain",
+		},
+		{
+			name:  "in progress",
+			games: []mlb.Game{withStatus(finalGame(1, nyy, 0, bal, 0), "I", "In Progress")},
+			want:  "The Baltimore Orioles are still playing",
+		},
+		{
+			name:  "doubleheader with a suspended game",
+			games: []mlb.Game{finalGame(1, nyy, 3, bal, 5), suspendedGame(finalGame(2, nyy, 4, bal, 2), time.Time{})},
+			want:  "Game 1 won 5-3, game 2 suspended (rain)",
+		},
+		{
+			name:  "doubleheader with a postponed game",
+			games: []mlb.Game{finalGame(1, nyy, 2, bal, 5), postponedGame(2, nyy, bal, "Rain")},
+			want:  "Game 1 won 5-2, game 2 postponed (rain)",
+		},
+		{
+			name:  "doubleheader with a tie",
+			games: []mlb.Game{finalGame(1, nyy, 2, bal, 5), finalGame(2, nyy, 3, bal, 3)},
+			want:  "Doubleheader! The Baltimore Orioles go 1 - 0 - 1",
+		},
+		{
+			name: "split squads",
+			games: []mlb.Game{
+				splitSquadGame(finalGame(1, nyy, 2, bal, 5)),
+				splitSquadGame(finalGame(2, bal, 1, bos, 4)),
+				splitSquadGame(finalGame(3, bal, 3, bos, 3)),
+			},
+			want: "Split squads go 1-1-1",
+		},
+		{
+			name:  "three games",
+			games: []mlb.Game{finalGame(1, nyy, 2, bal, 5), finalGame(2, nyy, 3, bal, 1), finalGame(3, nyy, 0, bal, 1)},
+			want:  "The Baltimore Orioles go 2-1 in 3 games",
+		},
+		{
+			name:  "doubleheader",
+			games: []mlb.Game{finalGame(1, nyy, 3, bal, 5), finalGame(2, nyy, 4, bal, 2)},
+			want:  "Doubleheader! The Baltimore Orioles go 1 - 1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := reporttest.NewSource()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, g := range tt.games {
+				src.AddGame("2022-05-28", g)
+			}
+
+			rg := NewReportGenerator(bal, src, time.UTC)
+			rg.Clock = clock.NewFake(time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC))
+
+			r, err := rg.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Headline != tt.want {
+				t.Errorf("got %q, want %q", r.Headline, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeriesHeadline(t *testing.T) {
+	orioles := SeriesTeam{Id: bal, Abbr: "BAL", Name: "Orioles", IsMyTeam: true}
+	yankees := SeriesTeam{Id: nyy, Abbr: "NYY", Name: "Yankees"}
+	with := func(t SeriesTeam, wins int) SeriesTeam {
+		t.Wins = wins
+		return t
+	}
+
+	tests := []struct {
+		name   string
+		series Series
+		want   string
+	}{
+		{
+			name:   "lead",
+			series: Series{Name: "ALDS", Teams: [2]SeriesTeam{with(orioles, 2), with(yankees, 1)}, NextGame: "Game 4 tonight 19:08"},
+			want:   "Orioles lead ALDS 2-1, game 4 tonight 19:08",
+		},
+		{
+			name:   "tied",
+			series: Series{Name: "ALCS", Teams: [2]SeriesTeam{with(orioles, 1), with(yankees, 1)}},
+			want:   "ALCS tied 1-1",
+		},
+		{
+			name:   "clinch",
+			series: Series{Name: "ALCS", Teams: [2]SeriesTeam{with(orioles, 4), with(yankees, 2)}, IsOver: true},
+			want:   "Orioles win the ALCS 4-2!",
+		},
+		{
+			name:   "world series",
+			series: Series{Name: "World Series", Teams: [2]SeriesTeam{with(orioles, 4), with(yankees, 3)}, IsOver: true},
+			want:   "The Orioles win the World Series!",
+		},
+		{
+			name:   "eliminated",
+			series: Series{Name: "AL Wild Card", Teams: [2]SeriesTeam{with(orioles, 0), with(yankees, 2)}, IsOver: true},
+			want:   "Orioles are eliminated, losing the AL Wild Card 0-2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.series.headline(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeasonHeadline(t *testing.T) {
+	season2022 := mlb.Season{
+		SeasonId:               "2022",
+		SpringStartDate:        "2022-03-17",
+		RegularSeasonStartDate: "2022-04-07",
+		LastDate1stHalf:        "2022-07-17",
+		FirstDate2ndHalf:       "2022-07-21",
+		RegularSeasonEndDate:   "2022-10-05",
+		PostSeasonEndDate:      "2022-11-05",
+	}
+	season2023 := mlb.Season{
+		SeasonId:         "2023",
+		SpringStartDate:  "2023-02-24",
+		LastDate1stHalf:  "2023-07-09",
+		FirstDate2ndHalf: "2023-07-14",
+	}
+
+	tests := []struct {
+		name     string
+		today    time.Time
+		nextYear bool
+		want     string
+	}{
+		{
+			name:  "regular season",
+			today: time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC),
+			want:  "Baseball Report Sunday 2022-05-29",
+		},
+		{
+			name:  "all-star break",
+			today: time.Date(2022, 7, 19, 7, 0, 0, 0, time.UTC),
+			want:  "All-Star break, back on July 21",
+		},
+		{
+			name:     "offseason",
+			today:    time.Date(2022, 12, 1, 7, 0, 0, 0, time.UTC),
+			nextYear: true,
+			want:     "85 days until spring training",
+		},
+		{
+			name:     "day before spring training",
+			today:    time.Date(2023, 2, 23, 7, 0, 0, 0, time.UTC),
+			nextYear: true,
+			want:     "Spring training starts tomorrow!",
+		},
+		{
+			name:  "next season not out yet",
+			today: time.Date(2022, 11, 10, 7, 0, 0, 0, time.UTC),
+			want:  "Baseball Report Thursday 2022-11-10",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := reporttest.NewSource()
+			if err != nil {
+				t.Fatal(err)
+			}
+			src.Seasons[2022] = season2022
+			if tt.nextYear {
+				src.Seasons[2023] = season2023
+			}
+
+			rg := NewReportGenerator(bal, src, time.UTC)
+
+			r, err := rg.GenerateReport(tt.today)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Headline != tt.want {
+				t.Errorf("got %q, want %q", r.Headline, tt.want)
+			}
+		})
+	}
+}
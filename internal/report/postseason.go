@@ -0,0 +1,288 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// rounds of the postseason, in the order they are played: wild card,
+// division series, league championship series and world series
+var rounds = []string{"F", "D", "L", "W"}
+
+// postseasonDays is how long the last round stays in the report after its last game
+const postseasonDays = 7
+
+// a series is identified by the round and the two teams playing it
+type seriesKey struct {
+	gameType string
+	teams    [2]int
+}
+
+type seriesGame struct {
+	date string
+	game mlb.Game
+}
+
+// fetchPostseason finds every series of the current round, which is the
+// latest one that has started. That is whether or not my team is still in it,
+// so the report keeps following along until the world series is over
+func (rg *ReportGenerator) fetchPostseason(today time.Time) (Postseason, error) {
+	s, err := rg.src.FetchPostseasonSchedule(today.Year())
+	if err != nil {
+		return Postseason{}, err
+	}
+
+	todayDate := today.Format(time.DateOnly)
+
+	round := -1
+	var lastPlayed string
+	for _, d := range s.Dates {
+		if d.Date >= todayDate {
+			continue
+		}
+
+		for _, g := range d.Games {
+			if !g.IsPostseason() {
+				continue
+			}
+
+			if r := roundOf(g.GameType); r > round {
+				round = r
+			}
+			if d.Date > lastPlayed {
+				lastPlayed = d.Date
+			}
+		}
+	}
+
+	if round == -1 || lastPlayed < today.AddDate(0, 0, -postseasonDays).Format(time.DateOnly) {
+		return Postseason{Series: []Series{}}, nil
+	}
+
+	var keys []seriesKey
+	games := make(map[seriesKey][]seriesGame)
+	for _, d := range s.Dates {
+		for _, g := range d.Games {
+			if g.GameType != rounds[round] {
+				continue
+			}
+
+			teams := [2]int{g.Teams.Away.Team.Id, g.Teams.Home.Team.Id}
+			if teams[0] > teams[1] {
+				teams[0], teams[1] = teams[1], teams[0]
+			}
+
+			k := seriesKey{gameType: g.GameType, teams: teams}
+			if _, ok := games[k]; !ok {
+				keys = append(keys, k)
+			}
+			games[k] = append(games[k], seriesGame{date: d.Date, game: g})
+		}
+	}
+
+	series := make([]Series, 0, len(keys))
+	for _, k := range keys {
+		series = append(series, rg.series(games[k], today))
+	}
+
+	// my series first, then by name
+	sort.SliceStable(series, func(i, j int) bool {
+		if series[i].IsMySeries != series[j].IsMySeries {
+			return series[i].IsMySeries
+		}
+		return series[i].Name < series[j].Name
+	})
+
+	return Postseason{Series: series}, nil
+}
+
+func roundOf(gameType string) int {
+	for i, r := range rounds {
+		if r == gameType {
+			return i
+		}
+	}
+	return -1
+}
+
+// series counts the wins of games before today, games later today don't count yet
+func (rg *ReportGenerator) series(games []seriesGame, today time.Time) Series {
+	todayDate := today.Format(time.DateOnly)
+	yesterdayDate := today.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	first := games[0].game
+	teams := [2]SeriesTeam{
+		rg.seriesTeam(first.Teams.Away.Team.Id),
+		rg.seriesTeam(first.Teams.Home.Team.Id),
+	}
+
+	var playedYesterday bool
+	for _, sg := range games {
+		state := gameState(sg.game.Status)
+		if sg.date >= todayDate || (state != GameFinal && state != GameCompletedEarly) {
+			continue
+		}
+
+		for i := range teams {
+			if (sg.game.Teams.Away.IsWinner && sg.game.Teams.Away.Team.Id == teams[i].Id) ||
+				(sg.game.Teams.Home.IsWinner && sg.game.Teams.Home.Team.Id == teams[i].Id) {
+				teams[i].Wins += 1
+			}
+		}
+		playedYesterday = playedYesterday || sg.date == yesterdayDate
+	}
+
+	// my team first, otherwise whoever is ahead
+	isMySeries := teams[0].Id == rg.MyTeamId || teams[1].Id == rg.MyTeamId
+	if teams[1].Id == rg.MyTeamId || (!isMySeries && teams[1].Wins > teams[0].Wins) {
+		teams[0], teams[1] = teams[1], teams[0]
+	}
+
+	// statsapi leaves GamesInSeries at 0 for some matchups, like ones that
+	// aren't seeded yet, and then there is no telling when the series is over
+	bestOf := first.GamesInSeries
+	isOver := bestOf > 0 && (teams[0].Wins > bestOf/2 || teams[1].Wins > bestOf/2)
+
+	var nextGame string
+	for _, sg := range games {
+		if isOver {
+			break
+		}
+
+		if sg.date >= todayDate && gameState(sg.game.Status) == GameNotStarted {
+			nextGame = fmt.Sprintf("Game %d %s", sg.game.SeriesGameNumber, rg.gameDay(sg.game.GameDate, today))
+			break
+		}
+	}
+
+	s := Series{
+		Name:            rg.seriesName(first.GameType, teams[0].Id),
+		Teams:           teams,
+		BestOf:          bestOf,
+		NextGame:        nextGame,
+		IsMySeries:      isMySeries,
+		IsOver:          isOver,
+		PlayedYesterday: playedYesterday,
+	}
+	s.Status = s.status()
+
+	return s
+}
+
+func (rg *ReportGenerator) seriesTeam(id int) SeriesTeam {
+	t := rg.team(id)
+	return SeriesTeam{
+		Id:       id,
+		Abbr:     t.Abbreviation,
+		Name:     t.TeamName,
+		IsMyTeam: id == rg.MyTeamId,
+	}
+}
+
+// seriesName is like AL Wild Card, ALDS, NLCS or World Series
+func (rg *ReportGenerator) seriesName(gameType string, teamId int) string {
+	// American League is AL
+	var league string
+	for _, w := range strings.Fields(rg.team(teamId).League.Name) {
+		league += w[:1]
+	}
+
+	switch gameType {
+	case "F":
+		return league + " Wild Card"
+	case "D":
+		return league + "DS"
+	case "L":
+		return league + "CS"
+	default:
+		return "World Series"
+	}
+}
+
+// gameDay is like "tonight 19:08", "tomorrow 13:03" or "Friday 10/13 16:07"
+func (rg *ReportGenerator) gameDay(t, today time.Time) string {
+	day := relativeDay(t, today, rg.Location)
+	if day == "today" && t.In(rg.Location).Hour() >= 17 {
+		day = "tonight"
+	}
+
+	return fmt.Sprintf("%s %s", day, t.In(rg.Location).Format("15:04"))
+}
+
+// status is like "Orioles lead 2-1", "Tied 1-1" or "Rangers win 3-0"
+func (s Series) status() string {
+	a, b := s.Teams[0], s.Teams[1]
+	if b.Wins > a.Wins {
+		a, b = b, a
+	}
+
+	switch {
+	case s.IsOver:
+		return fmt.Sprintf("%s win %d-%d", a.Name, a.Wins, b.Wins)
+	case a.Wins == b.Wins:
+		return fmt.Sprintf("Tied %d-%d", a.Wins, b.Wins)
+	default:
+		return fmt.Sprintf("%s lead %d-%d", a.Name, a.Wins, b.Wins)
+	}
+}
+
+// headline is from the point of view of the first team, which is my team
+// when it is in the series: "Orioles lead ALDS 2-1, game 4 tonight 19:08"
+func (s Series) headline() string {
+	me, them := s.Teams[0], s.Teams[1]
+
+	var headline string
+	switch {
+	case s.IsOver && me.Wins > them.Wins && s.Name == "World Series":
+		headline = fmt.Sprintf("The %s win the World Series!", me.Name)
+	case s.IsOver && me.Wins > them.Wins:
+		headline = fmt.Sprintf("%s win the %s %d-%d!", me.Name, s.Name, me.Wins, them.Wins)
+	case s.IsOver:
+		headline = fmt.Sprintf("%s are eliminated, losing the %s %d-%d", me.Name, s.Name, me.Wins, them.Wins)
+	case me.Wins > them.Wins:
+		headline = fmt.Sprintf("%s lead %s %d-%d", me.Name, s.Name, me.Wins, them.Wins)
+	case me.Wins < them.Wins:
+		headline = fmt.Sprintf("%s trail %s %d-%d", me.Name, s.Name, me.Wins, them.Wins)
+	default:
+		headline = fmt.Sprintf("%s tied %d-%d", s.Name, me.Wins, them.Wins)
+	}
+
+	if s.NextGame != "" {
+		headline += ", " + strings.ToLower(s.NextGame[:1]) + s.NextGame[1:]
+	}
+
+	return headline
+}
+
+// postseasonHeadline replaces the usual headline with the state of my team's
+// series, or when my team didn't play, with a series that was played yesterday.
+// Postponed and suspended games keep their own headline
+func postseasonHeadline(pastGames []PastGame, p Postseason) (string, bool) {
+	for _, g := range pastGames {
+		if !g.Decided() {
+			return "", false
+		}
+	}
+
+	for _, s := range p.Series {
+		if s.IsMySeries && (s.PlayedYesterday || !s.IsOver) {
+			return s.headline(), true
+		}
+	}
+
+	if len(pastGames) > 0 {
+		return "", false
+	}
+
+	for _, s := range p.Series {
+		if s.PlayedYesterday {
+			return s.headline(), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+const tex = 140
+
+func divisionSeriesGame(number, gamesInSeries int, g mlb.Game, gameDate time.Time) mlb.Game {
+	g.GameType = "D"
+	g.SeriesGameNumber = number
+	g.GamesInSeries = gamesInSeries
+	g.GameDate = gameDate
+	return g
+}
+
+func TestSeries(t *testing.T) {
+	today := time.Date(2023, 10, 10, 7, 0, 0, 0, time.UTC)
+	scheduled := mlb.Status{AbstractGameState: "Preview", CodedGameState: "S", DetailedState: "Scheduled"}
+
+	tests := []struct {
+		name string
+		// gamesInSeries is what statsapi says for every game
+		gamesInSeries int
+		want          Series
+	}{
+		{
+			name:          "best of 3",
+			gamesInSeries: 3,
+			want:          Series{BestOf: 3, Status: "Orioles win 2-0", IsOver: true},
+		},
+		{
+			// not seeded yet, or statsapi just didn't fill it in
+			name:          "unknown length",
+			gamesInSeries: 0,
+			want:          Series{Status: "Orioles lead 2-0", NextGame: "Game 3 tonight 19:07"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := reporttest.NewSource()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src.AddGame("2023-10-08", divisionSeriesGame(1, tt.gamesInSeries, finalGame(1, tex, 2, bal, 5), time.Date(2023, 10, 8, 17, 3, 0, 0, time.UTC)))
+			src.AddGame("2023-10-09", divisionSeriesGame(2, tt.gamesInSeries, finalGame(2, tex, 1, bal, 4), time.Date(2023, 10, 9, 17, 3, 0, 0, time.UTC)))
+			game3 := divisionSeriesGame(3, tt.gamesInSeries, finalGame(3, bal, 0, tex, 0), time.Date(2023, 10, 10, 19, 7, 0, 0, time.UTC))
+			game3.Status = scheduled
+			game3.Teams.Home.IsWinner = false
+			game3.Teams.Away.IsWinner = false
+			src.AddGame("2023-10-10", game3)
+
+			rg := NewReportGenerator(bal, src, time.UTC)
+			p, err := rg.fetchPostseason(today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(p.Series) != 1 {
+				t.Fatalf("got %d series, want 1", len(p.Series))
+			}
+
+			s := p.Series[0]
+			if s.BestOf != tt.want.BestOf || s.Status != tt.want.Status || s.NextGame != tt.want.NextGame || s.IsOver != tt.want.IsOver {
+				t.Errorf("got best of %d %q %q over %t, want best of %d %q %q over %t",
+					s.BestOf, s.Status, s.NextGame, s.IsOver,
+					tt.want.BestOf, tt.want.Status, tt.want.NextGame, tt.want.IsOver,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// probablePitcher adds the season line to an announced starter,
+// who is still worth showing without it
+func probablePitcher(p mlb.ProbablePitcher, g mlb.Game) ProbablePitcher {
+	if p.Id == 0 {
+		return ProbablePitcher{}
+	}
+
+	pp := ProbablePitcher{
+		Id:       p.Id,
+		Name:     p.FullName,
+		LastName: lastName(p.FullName),
+	}
+
+	season := g.Season
+	if season == "" {
+		season = strconv.Itoa(g.GameDate.Year())
+	}
+
+	stats, ok := p.SeasonPitching(season)
+	if !ok {
+		return pp
+	}
+
+	pp.HasStats = true
+	pp.Wins = stats.Wins
+	pp.Losses = stats.Losses
+	pp.Era = stats.Era
+	return pp
+}
+
+// lastName is what a pitcher goes by in a box score, like Cole,
+// or McCullers for Lance McCullers Jr.
+func lastName(fullName string) string {
+	names := strings.Fields(fullName)
+	for len(names) > 1 {
+		switch names[len(names)-1] {
+		case "Jr.", "Sr.", "II", "III", "IV":
+			names = names[:len(names)-1]
+			continue
+		}
+		break
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+	return names[len(names)-1]
+}
+
+// HasProbables is true once either starter is announced
+func (g FutureGame) HasProbables() bool {
+	return g.MyPitcher.Id != 0 || g.TheirPitcher.Id != 0
+}
+
+// Matchup is like "Bradish vs. Cole", my team's starter first
+func (g FutureGame) Matchup() string {
+	return g.MyPitcher.short() + " vs. " + g.TheirPitcher.short()
+}
+
+// Records is like "4-3, 3.12 vs. 8-1, 2.78", empty when neither line is known
+func (g FutureGame) Records() string {
+	if !g.MyPitcher.HasStats && !g.TheirPitcher.HasStats {
+		return ""
+	}
+	return g.MyPitcher.record() + " vs. " + g.TheirPitcher.record()
+}
+
+func (p ProbablePitcher) short() string {
+	if p.Id == 0 {
+		return "TBD"
+	}
+	return p.LastName
+}
+
+func (p ProbablePitcher) record() string {
+	if !p.HasStats {
+		return "-"
+	}
+	return fmt.Sprintf("%d-%d, %s", p.Wins, p.Losses, p.Era)
+}
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+func TestProbablePitchers(t *testing.T) {
+	src, err := reporttest.NewSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := mlb.Game{
+		GamePk:   1,
+		Season:   "2023",
+		GameDate: time.Date(2023, 6, 2, 23, 5, 0, 0, time.UTC),
+	}
+	g.Teams.Away.Team.Id = nyy
+	// Cole's line is missing, which happens to pitchers yet to pitch this season
+	g.Teams.Away.ProbablePitcher = mlb.ProbablePitcher{Person: mlb.Person{Id: 543037, FullName: "Gerrit Cole"}}
+	g.Teams.Home.Team.Id = bal
+	// as hydrated into the schedule by statsapi
+	err = json.Unmarshal([]byte(`{
+		"id": 680694,
+		"fullName": "Kyle Bradish",
+		"stats": [{
+			"type": {"displayName": "statsSingleSeason"},
+			"group": {"displayName": "pitching"},
+			"splits": [
+				{"season": "2022", "stat": {"wins": 4, "losses": 7, "era": "4.90"}},
+				{"season": "2023", "stat": {"wins": 4, "losses": 3, "era": "3.12"}}
+			]
+		}]
+	}`), &g.Teams.Home.ProbablePitcher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddGame("2023-06-02", g)
+
+	// not announced yet
+	g2 := g
+	g2.GamePk = 2
+	g2.GameDate = g.GameDate.AddDate(0, 0, 1)
+	g2.Teams.Away.ProbablePitcher = mlb.ProbablePitcher{}
+	g2.Teams.Home.ProbablePitcher = mlb.ProbablePitcher{}
+	src.AddGame("2023-06-03", g2)
+
+	rg := NewReportGenerator(bal, src, time.UTC)
+	r, err := rg.GenerateReport(time.Date(2023, 6, 1, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Upcoming.HasProbables {
+		t.Fatal("no probable pitchers")
+	}
+
+	fg := r.Upcoming.FutureDays[1].Games[0]
+	if got := fg.Matchup(); got != "Bradish vs. Cole" {
+		t.Errorf("got matchup %q", got)
+	}
+	if got := fg.Records(); got != "4-3, 3.12 vs. -" {
+		t.Errorf("got records %q", got)
+	}
+
+	if fg := r.Upcoming.FutureDays[2].Games[0]; fg.HasProbables() {
+		t.Errorf("got probables %q before they were announced", fg.Matchup())
+	}
+
+	rendered, err := rg.Render(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rendered, "Bradish vs. Cole<br><small>4-3, 3.12 vs. -</small>") {
+		t.Errorf("probable pitchers are missing from the report\n%s", rendered)
+	}
+}
+
+func TestLastName(t *testing.T) {
+	tests := map[string]string{
+		"Gerrit Cole":         "Cole",
+		"Lance McCullers Jr.": "McCullers",
+		"Luis Ortiz":          "Ortiz",
+		"Ohtani":              "Ohtani",
+		"":                    "",
+	}
+
+	for fullName, want := range tests {
+		if got := lastName(fullName); got != want {
+			t.Errorf("lastName(%q) is %q, want %q", fullName, got, want)
+		}
+	}
+}
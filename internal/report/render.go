@@ -8,7 +8,16 @@ import (
 
 // PastGame is used by past-game.html.tpl
 type PastGame struct {
-	PostponeReason   string
+	GamePk int
+	State  GameState
+	// Reason is why the game was postponed, suspended, delayed, or called early
+	Reason string
+	// PostponeReason is Reason, but only for postponed games
+	PostponeReason string
+	// ResumesAt is set for suspended games, like "tomorrow at 12:05"
+	ResumesAt string
+	// ResumedFrom is set for games finished on a later day, like "May 27"
+	ResumedFrom      string
 	Venue            mlb.Venue
 	IsWinnerHome     bool
 	W                mlb.GameTeam
@@ -16,6 +25,18 @@ type PastGame struct {
 	CondensedGameUrl string
 	HasLinescore     bool
 	Linescore        Linescore
+	HasBoxscore      bool
+	Boxscore         Boxscore
+	ScoringPlays     []ScoringPlay
+}
+
+// ScoringPlay is used by scoring-plays.html.tpl
+type ScoringPlay struct {
+	// Inning is like "Top 3rd"
+	Inning      string
+	Description string
+	// Score is after the play, away team first
+	Score string
 }
 
 type Linescore struct {
@@ -31,11 +52,57 @@ type LinescoreTeam struct {
 	Errors  int
 }
 
+// Boxscore is used by boxscore.html.tpl
+type Boxscore struct {
+	// Starters is the away starter, then the home starter
+	Starters []PitchingLine
+	// Decisions are the winning, losing and save pitchers, in that order
+	Decisions []PitchingLine
+	// TopHitters only includes hitters of my team
+	TopHitters []BattingLine
+}
+
+type PitchingLine struct {
+	Name           string
+	Abbr           string
+	Note           string
+	InningsPitched string
+	Hits           int
+	Runs           int
+	EarnedRuns     int
+	BaseOnBalls    int
+	StrikeOuts     int
+}
+
+type BattingLine struct {
+	Name        string
+	AtBats      int
+	Runs        int
+	Hits        int
+	HomeRuns    int
+	Rbi         int
+	BaseOnBalls int
+}
+
 type FutureGame struct {
 	// GameTimeLocal is when the game is on in the rss feed's timezone
 	GameTimeLocal string
 	IsMyTeamHome  bool
 	AgainstAbbr   string
+	// MyPitcher and TheirPitcher are the probable starters, Id is 0 until announced
+	MyPitcher    ProbablePitcher
+	TheirPitcher ProbablePitcher
+}
+
+type ProbablePitcher struct {
+	Id       int
+	Name     string
+	LastName string
+	// HasStats is false when the season line couldn't be fetched
+	HasStats bool
+	Wins     int
+	Losses   int
+	Era      string
 }
 
 type Yesterday struct {
@@ -54,12 +121,138 @@ type Upcoming struct {
 	FutureDays [8]FutureDay
 	// Timezone is the rss feed's timezone
 	Timezone string
+	// HasProbables is true when any upcoming game has an announced starter
+	HasProbables bool
+}
+
+// Standings is used by standings.html.tpl
+type Standings struct {
+	Division StandingsTable
+	WildCard StandingsTable
+}
+
+type StandingsTable struct {
+	Name string
+	Rows []StandingsRow
+}
+
+type StandingsRow struct {
+	Abbr            string
+	Wins            int
+	Losses          int
+	Pct             string
+	GamesBack       string
+	LastTen         string
+	Streak          string
+	RunDifferential int
+	IsMyTeam        bool
+}
+
+// Postseason is used by postseason.html.tpl
+type Postseason struct {
+	Series []Series
+}
+
+type Series struct {
+	// Name is like ALDS, NL Wild Card or World Series
+	Name string
+	// Teams has my team first when it is in the series, otherwise whoever is ahead
+	Teams [2]SeriesTeam
+	// BestOf is 0 when statsapi doesn't know yet, the series is never over then
+	BestOf int
+	// Status is like "Orioles lead 2-1", "Tied 1-1" or "Rangers win 3-0"
+	Status string
+	// NextGame is like "Game 4 tonight 19:08", empty once the series is over
+	NextGame        string
+	IsMySeries      bool
+	IsOver          bool
+	PlayedYesterday bool
+}
+
+type SeriesTeam struct {
+	Id   int
+	Abbr string
+	// Name is the short one, like Orioles
+	Name     string
+	Wins     int
+	IsMyTeam bool
+}
+
+// Offseason is used by offseason.html.tpl
+type Offseason struct {
+	// Season is the one coming up, like 2024
+	Season          string
+	DaysUntilSpring int
+	// SpringStart is the first spring training game, like February 22
+	SpringStart     string
+	SpringStartDate string
+}
+
+// Digest is used by digest.html.tpl, a week of offseason moves
+type Digest struct {
+	Groups []MoveGroup
+}
+
+type MoveGroup struct {
+	// Name is like Signings or Trades
+	Name  string
+	Moves []Move
+}
+
+type Move struct {
+	// Day is like Nov 2
+	Day         string
+	Date        string
+	Description string
+}
+
+// RosterPlayer is one player of the 40 man roster
+type RosterPlayer struct {
+	Id       int
+	Name     string
+	Position string
+	// Status is like A (active), D15 (15-day injured list) or MIN (in the minors)
+	Status            string
+	StatusDescription string
+}
+
+// RosterMove is used by roster-moves.html.tpl
+type RosterMove struct {
+	PlayerId int
+	Name     string
+	Position string
+	// Move is like "Placed on the 15-day IL" or "Called up"
+	Move string
+	// Details is the transaction behind the move, if there is one
+	Details string
 }
 
 type Report struct {
-	Yesterday Yesterday
-	Upcoming  Upcoming
-	Headline  string
-	Link      string
-	When      time.Time
+	Yesterday    Yesterday
+	Upcoming     Upcoming
+	HasStandings bool
+	Standings    Standings
+	// HasPostseason is only true in October, when there are series to show
+	HasPostseason bool
+	Postseason    Postseason
+	// Phase is the part of the season the report is for, empty if it isn't known
+	Phase mlb.Phase
+	// HasOffseason is only true in the offseason, once next season's dates are out
+	HasOffseason bool
+	Offseason    Offseason
+	// HasDigest is only true in the offseason, when my team made some moves
+	HasDigest bool
+	Digest    Digest
+	// Roster is kept to find the RosterMoves of the next report
+	Roster         []RosterPlayer
+	HasRosterMoves bool
+	RosterMoves    []RosterMove
+	Headline       string
+	Link           string
+	When           time.Time
+	// Updated is when the report last changed in place, zero if it never has
+	Updated time.Time
+	// Revision is bumped when the report is updated after the fact,
+	// and that update should show up as a new item in the feed
+	Revision int
 }
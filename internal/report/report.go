@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"html/template"
 	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/0queue/mlb-rss/internal/clock"
 	"github.com/0queue/mlb-rss/internal/mlb"
 	"github.com/0queue/mlb-rss/ui"
 )
@@ -16,34 +20,54 @@ const BaseballTheaterTimeFormat = "20060102"
 
 type ReportGenerator struct {
 	MyTeamId int
-	mc       *mlb.MlbClient
+	src      Source
 	Location *time.Location
+	// Clock decides what day Generate makes a report for
+	Clock clock.Clock
+	// BoxScore adds pitching and batting lines to yesterday's games
+	BoxScore bool
+	// Calendar can be shared between generators, so seasons are only fetched once
+	Calendar *Calendar
 	t        *template.Template
 }
 
-func NewReportGenerator(myTeamId int, mc *mlb.MlbClient, loc *time.Location) ReportGenerator {
+func NewReportGenerator(myTeamId int, src Source, loc *time.Location) ReportGenerator {
 	funcs := template.FuncMap{
 		"inc": func(i int) int {
 			return i + 1
 		},
+		// signed shows the + on positive numbers, like a run differential
+		"signed": func(i int) string {
+			if i > 0 {
+				return fmt.Sprintf("+%d", i)
+			}
+			return strconv.Itoa(i)
+		},
 	}
 
 	return ReportGenerator{
 		MyTeamId: myTeamId,
-		mc:       mc,
+		src:      src,
 		Location: loc,
+		Clock:    clock.System,
+		Calendar: NewCalendar(src),
 		t:        template.Must(template.New("").Funcs(funcs).ParseFS(ui.ReportTemplates, "*.html.tpl")),
 	}
 }
 
+// Generate is GenerateReport for today, according to the Clock
+func (rg *ReportGenerator) Generate() (Report, error) {
+	return rg.GenerateReport(rg.Clock.Now())
+}
+
 // Do analysis of the games and generate the report. NO TEMPLATES
 // assumes the first Date is yesterday, and the rest are the future
 // ultimately, analysis consists of filtering
 func (rg *ReportGenerator) GenerateReport(today time.Time) (Report, error) {
 
-	s, err := rg.mc.FetchSchedule(today.AddDate(0, 0, -1), today.AddDate(0, 0, 7), rg.MyTeamId)
+	s, err := rg.src.FetchSchedule(today.AddDate(0, 0, -1), today.AddDate(0, 0, 7), rg.MyTeamId)
 	if err != nil {
-		return Report{}, nil
+		return Report{}, err
 	}
 
 	pastGames := rg.analyzePastGames(s.Dates, rg.MyTeamId, today)
@@ -53,26 +77,96 @@ func (rg *ReportGenerator) GenerateReport(today time.Time) (Report, error) {
 	link := fmt.Sprintf("https://baseball.theater/games/%s", baseballTheaterDate)
 
 	yesterday := Yesterday{
-		MyTeamName:      rg.mc.AllTeams[rg.MyTeamId].Name,
+		MyTeamName:      rg.team(rg.MyTeamId).Name,
 		PastGames:       pastGames,
 		BaseballTheater: link,
 	}
 
-	tz, _ := today.Local().Zone()
+	tz, _ := today.In(rg.Location).Zone()
 
 	upcoming := Upcoming{
 		FutureDays: futureGames,
 		Timezone:   tz,
 	}
+	for _, fd := range futureGames {
+		for _, fg := range fd.Games {
+			upcoming.HasProbables = upcoming.HasProbables || fg.HasProbables()
+		}
+	}
+
+	phase, season, err := rg.Calendar.Phase(today)
+	if err != nil {
+		slog.Warn("Failed to fetch season", slog.String("err", err.Error()))
+	}
+
+	offseason, hasOffseason := rg.offseason(phase, season, today)
+
+	var hasDigest bool
+	var digest Digest
+	if phase == mlb.PhaseOffseason {
+		digest, err = rg.fetchDigest(today)
+		hasDigest = err == nil && digest.Moves() > 0
+		if err != nil {
+			slog.Warn("Failed to fetch transactions", slog.String("err", err.Error()))
+		}
+	}
+
+	// the offseason has the digest instead
+	var roster []RosterPlayer
+	if phase != mlb.PhaseOffseason {
+		roster, err = rg.fetchRoster(today)
+		if err != nil {
+			slog.Warn("Failed to fetch roster", slog.String("err", err.Error()))
+		}
+	}
+
+	postseason, err := rg.fetchPostseason(today)
+	hasPostseason := err == nil && len(postseason.Series) > 0
+	if err != nil {
+		slog.Warn("Failed to fetch postseason", slog.String("err", err.Error()))
+	}
+
+	// the regular season standings are old news in the postseason, and the offseason
+	var hasStandings bool
+	var standings Standings
+	if !hasPostseason && phase != mlb.PhaseOffseason {
+		standings, err = rg.fetchStandings(today)
+		hasStandings = err == nil
+		if err != nil {
+			slog.Warn("Failed to fetch standings", slog.String("err", err.Error()))
+		}
+	}
 
 	headline := rg.generateHeadline(pastGames, today)
+	if len(pastGames) == 0 && hasOffseason && hasDigest {
+		headline = offseason.headline() + ", " + digest.headline()
+	} else if len(pastGames) == 0 && hasOffseason {
+		headline = offseason.headline()
+	} else if len(pastGames) == 0 && hasDigest {
+		headline = digest.headline()
+	} else if len(pastGames) == 0 && phase == mlb.PhaseAllStarBreak {
+		headline = "All-Star break, back on " + rg.seasonDate(season.FirstDate2ndHalf)
+	}
+	if h, ok := postseasonHeadline(pastGames, postseason); ok {
+		headline = h
+	}
 
 	return Report{
-		Yesterday: yesterday,
-		Upcoming:  upcoming,
-		Headline:  headline,
-		Link:      link,
-		When:      today,
+		Yesterday:     yesterday,
+		Upcoming:      upcoming,
+		HasStandings:  hasStandings,
+		Standings:     standings,
+		HasPostseason: hasPostseason,
+		Postseason:    postseason,
+		Phase:         phase,
+		HasOffseason:  hasOffseason,
+		Offseason:     offseason,
+		HasDigest:     hasDigest,
+		Digest:        digest,
+		Roster:        roster,
+		Headline:      headline,
+		Link:          link,
+		When:          today,
 	}, nil
 }
 
@@ -80,11 +174,31 @@ func (rg *ReportGenerator) GenerateReport(today time.Time) (Report, error) {
 func (rg *ReportGenerator) Render(r Report) (string, error) {
 	var content bytes.Buffer
 	err := rg.t.ExecuteTemplate(&content, "report.html.tpl", struct {
-		Yesterday Yesterday
-		Upcoming  Upcoming
+		Yesterday      Yesterday
+		Upcoming       Upcoming
+		HasStandings   bool
+		Standings      Standings
+		HasPostseason  bool
+		Postseason     Postseason
+		HasOffseason   bool
+		Offseason      Offseason
+		HasDigest      bool
+		Digest         Digest
+		HasRosterMoves bool
+		RosterMoves    []RosterMove
 	}{
-		Yesterday: r.Yesterday,
-		Upcoming:  r.Upcoming,
+		Yesterday:      r.Yesterday,
+		Upcoming:       r.Upcoming,
+		HasStandings:   r.HasStandings,
+		Standings:      r.Standings,
+		HasPostseason:  r.HasPostseason,
+		Postseason:     r.Postseason,
+		HasOffseason:   r.HasOffseason,
+		Offseason:      r.Offseason,
+		HasDigest:      r.HasDigest,
+		Digest:         r.Digest,
+		HasRosterMoves: r.HasRosterMoves,
+		RosterMoves:    r.RosterMoves,
 	})
 	if err != nil {
 		return "", err
@@ -95,15 +209,35 @@ func (rg *ReportGenerator) Render(r Report) (string, error) {
 func (rg *ReportGenerator) RenderWeb(r Report) (string, error) {
 	var content bytes.Buffer
 	err := rg.t.ExecuteTemplate(&content, "web.html.tpl", struct {
-		Title     string
-		H2        string
-		Yesterday Yesterday
-		Upcoming  Upcoming
+		Title          string
+		H2             string
+		Yesterday      Yesterday
+		Upcoming       Upcoming
+		HasStandings   bool
+		Standings      Standings
+		HasPostseason  bool
+		Postseason     Postseason
+		HasOffseason   bool
+		Offseason      Offseason
+		HasDigest      bool
+		Digest         Digest
+		HasRosterMoves bool
+		RosterMoves    []RosterMove
 	}{
-		Title:     r.Headline,
-		H2:        r.Headline,
-		Yesterday: r.Yesterday,
-		Upcoming:  r.Upcoming,
+		Title:          r.Headline,
+		H2:             r.Headline,
+		Yesterday:      r.Yesterday,
+		Upcoming:       r.Upcoming,
+		HasStandings:   r.HasStandings,
+		Standings:      r.Standings,
+		HasPostseason:  r.HasPostseason,
+		Postseason:     r.Postseason,
+		HasOffseason:   r.HasOffseason,
+		Offseason:      r.Offseason,
+		HasDigest:      r.HasDigest,
+		Digest:         r.Digest,
+		HasRosterMoves: r.HasRosterMoves,
+		RosterMoves:    r.RosterMoves,
 	})
 	if err != nil {
 		return "", err
@@ -112,6 +246,12 @@ func (rg *ReportGenerator) RenderWeb(r Report) (string, error) {
 	return content.String(), nil
 }
 
+// team is the team with the given id, or an empty one if the Source doesn't know it
+func (rg *ReportGenerator) team(id int) mlb.Team {
+	t, _ := rg.src.Team(id)
+	return t
+}
+
 // keep games involving the team with the given id
 func filterMyTeam(dates []mlb.Date, id int) []mlb.Date {
 	newDates := make([]mlb.Date, 0)
@@ -156,8 +296,11 @@ func (rg *ReportGenerator) analyzePastGames(dates []mlb.Date, id int, today time
 
 	for _, g := range date.Games {
 
+		state := gameState(g.Status)
+
 		// TODO doesn't really handle ties well
-		var isWinnerHome = g.Teams.Home.IsWinner
+		// only decided games have a winner, in the rest W is just the away team
+		var isWinnerHome = (state == GameFinal || state == GameCompletedEarly) && g.Teams.Home.IsWinner
 		var winner mlb.GameTeam
 		var loser mlb.GameTeam
 
@@ -169,36 +312,91 @@ func (rg *ReportGenerator) analyzePastGames(dates []mlb.Date, id int, today time
 			loser = g.Teams.Home
 		}
 
-		var postponeReason string
-		if g.Status.DetailedState == "Postponed" {
-			postponeReason = g.Status.Reason
+		// final games sometimes have one too, like "Final: Tied", which isn't much of a reason
+		var reason, postponeReason string
+		if state != GameFinal {
+			reason = stateReason(g.Status)
+		}
+		if state == GamePostponed {
+			postponeReason = reason
 		}
 
-		u, err := rg.fetchCondensedGame(g.GamePk)
-		if err != nil {
-			slog.Warn(
-				"Failed to fetch condensed game",
-				slog.Int("gamePk", g.GamePk),
-				slog.String("err", err.Error()),
+		var resumes, resumedFrom string
+		if state == GameSuspended && !g.ResumeDate.IsZero() {
+			resumes = resumesAt(g.ResumeDate, today, rg.Location)
+		}
+		if !g.ResumedFrom.IsZero() {
+			resumedFrom = g.ResumedFrom.In(rg.Location).Format("January 2")
+		}
+
+		var u string
+		var err error
+		if state == GameFinal || state == GameCompletedEarly {
+			u, err = rg.fetchCondensedGame(g.GamePk)
+			if err != nil {
+				slog.Warn(
+					"Failed to fetch condensed game",
+					slog.Int("gamePk", g.GamePk),
+					slog.String("err", err.Error()),
+				)
+			}
+		}
+
+		var hasLinescore bool
+		var l Linescore
+		if state.Started() {
+			l, err = rg.fetchLinescore(
+				g.GamePk,
+				g.Teams.Home.Team.Id,
+				g.Teams.Away.Team.Id,
 			)
+			hasLinescore = err == nil
+			if err != nil {
+				slog.Warn(
+					"Failed to fetch linescore",
+					slog.Int("gamePk", g.GamePk),
+					slog.String("err", err.Error()),
+				)
+			}
 		}
 
-		l, err := rg.fetchLinescore(
-			g.GamePk,
-			g.Teams.Home.Team.Id,
-			g.Teams.Away.Team.Id,
-		)
-		hasLinescore := err == nil
-		if err != nil {
-			slog.Warn(
-				"Failed to fetch linescore",
-				slog.Int("gamePk", g.GamePk),
-				slog.String("err", err.Error()),
+		var hasBoxscore bool
+		var b Boxscore
+		if rg.BoxScore && state.Started() {
+			b, err = rg.fetchBoxscore(g.GamePk)
+			hasBoxscore = err == nil
+			if err != nil {
+				slog.Warn(
+					"Failed to fetch boxscore",
+					slog.Int("gamePk", g.GamePk),
+					slog.String("err", err.Error()),
+				)
+			}
+		}
+
+		var scoringPlays []ScoringPlay
+		if state.Started() {
+			scoringPlays, err = rg.fetchScoringPlays(
+				g.GamePk,
+				g.Teams.Home.Team.Id,
+				g.Teams.Away.Team.Id,
 			)
+			if err != nil {
+				slog.Warn(
+					"Failed to fetch scoring plays",
+					slog.Int("gamePk", g.GamePk),
+					slog.String("err", err.Error()),
+				)
+			}
 		}
 
 		p := PastGame{
+			GamePk:           g.GamePk,
+			State:            state,
+			Reason:           reason,
 			PostponeReason:   postponeReason,
+			ResumesAt:        resumes,
+			ResumedFrom:      resumedFrom,
 			Venue:            g.Venue,
 			IsWinnerHome:     isWinnerHome,
 			W:                winner,
@@ -206,6 +404,9 @@ func (rg *ReportGenerator) analyzePastGames(dates []mlb.Date, id int, today time
 			CondensedGameUrl: u,
 			HasLinescore:     hasLinescore,
 			Linescore:        l,
+			HasBoxscore:      hasBoxscore,
+			Boxscore:         b,
+			ScoringPlays:     scoringPlays,
 		}
 
 		pastGames = append(pastGames, p)
@@ -240,17 +441,19 @@ func (rg *ReportGenerator) analyzeFutureGames(today time.Time, dates []mlb.Date)
 		for _, g := range gs {
 
 			isHome := g.Teams.Home.Team.Id == rg.MyTeamId
-			var opponentTeam mlb.GameTeam
+			var myTeam, opponentTeam mlb.GameTeam
 			if isHome {
-				opponentTeam = g.Teams.Away
+				myTeam, opponentTeam = g.Teams.Home, g.Teams.Away
 			} else {
-				opponentTeam = g.Teams.Home
+				myTeam, opponentTeam = g.Teams.Away, g.Teams.Home
 			}
 
 			futureGame := FutureGame{
 				GameTimeLocal: g.GameDate.In(rg.Location).Format("15:04"),
 				IsMyTeamHome:  isHome,
-				AgainstAbbr:   rg.mc.AllTeams[opponentTeam.Team.Id].Abbreviation,
+				AgainstAbbr:   rg.team(opponentTeam.Team.Id).Abbreviation,
+				MyPitcher:     probablePitcher(myTeam.ProbablePitcher, g),
+				TheirPitcher:  probablePitcher(opponentTeam.ProbablePitcher, g),
 			}
 
 			games = append(games, futureGame)
@@ -271,51 +474,190 @@ func (rg *ReportGenerator) analyzeFutureGames(today time.Time, dates []mlb.Date)
 
 func (rg *ReportGenerator) generateHeadline(pastGames []PastGame, today time.Time) string {
 	// 1. no games
-	// 2. Postpone
+	// 2. Postpone, cancel, suspend, delay, or not over yet (see gameHeadline)
 	// 3. team wins! 1 of 1
 	// 4. team loses :( 1 of 1
 	// 5. team ties? guess so
-	// 6. Double header
+	// 6. Double header, or split squads, or any other number of games (see gamesHeadline)
 
-	myTeamName := rg.mc.AllTeams[rg.MyTeamId].Name
+	myTeamName := rg.team(rg.MyTeamId).Name
 
 	switch len(pastGames) {
 	case 0:
 		return fmt.Sprintf("Baseball Report %s", today.Format("Monday 2006-01-02"))
 	case 1:
-		var headline string
-		g := pastGames[0]
-		if g.PostponeReason != "" {
-			headline = fmt.Sprintf("Game was postponed due to %s", g.PostponeReason)
-		} else if g.W.Score == g.L.Score {
-			headline = fmt.Sprintf("The %s tie, %d to %d", myTeamName, g.W.Score, g.L.Score)
-		} else if g.W.Team.Id == rg.MyTeamId {
-			headline = fmt.Sprintf("The %s win! %d to %d", myTeamName, g.W.Score, g.L.Score)
-		} else {
-			headline = fmt.Sprintf("The %s lose, %d to %d", myTeamName, g.L.Score, g.W.Score)
-		}
-		return headline
-	case 2:
-		var winCount int
-		var lossCount int
-		for _, g := range pastGames {
-			if g.W.Team.Id == rg.MyTeamId {
-				winCount += 1
-			} else {
-				lossCount += 1
-			}
+		return rg.gameHeadline(pastGames[0], myTeamName)
+	default:
+		return rg.gamesHeadline(pastGames, myTeamName)
+	}
+}
+
+// gamesHeadline sums up the record of the day when every game was decided,
+// otherwise it goes through the games one by one, like
+// "Game 1 won 5-2, game 2 postponed (rain)"
+func (rg *ReportGenerator) gamesHeadline(pastGames []PastGame, myTeamName string) string {
+	var wins, losses, ties int
+	var splitSquad bool
+	decided := true
+	for _, g := range pastGames {
+		mine, theirs := g.scores(rg.MyTeamId)
+		splitSquad = splitSquad || mine.SplitSquad
+
+		switch {
+		case !g.Decided():
+			decided = false
+		case mine.Score > theirs.Score:
+			wins += 1
+		case mine.Score < theirs.Score:
+			losses += 1
+		default:
+			ties += 1
+		}
+	}
+
+	if decided {
+		record := fmt.Sprintf("%d-%d", wins, losses)
+		if ties > 0 {
+			record += fmt.Sprintf("-%d", ties)
+		}
+
+		switch {
+		case splitSquad:
+			return fmt.Sprintf("Split squads go %s", record)
+		case len(pastGames) == 2 && ties == 0:
+			return fmt.Sprintf("Doubleheader! The %s go %d - %d", myTeamName, wins, losses)
+		case len(pastGames) == 2:
+			return fmt.Sprintf("Doubleheader! The %s go %d - %d - %d", myTeamName, wins, losses, ties)
+		default:
+			return fmt.Sprintf("The %s go %s in %d games", myTeamName, record, len(pastGames))
+		}
+	}
+
+	summaries := make([]string, 0, len(pastGames))
+	for i, g := range pastGames {
+		game := "game"
+		if i == 0 {
+			game = "Game"
+		}
+		summaries = append(summaries, fmt.Sprintf("%s %d %s", game, i+1, rg.gameSummary(g)))
+	}
+
+	return strings.Join(summaries, ", ")
+}
+
+// gameSummary is a few words on how a game went, like "won 5-2" or "postponed (rain)"
+func (rg *ReportGenerator) gameSummary(g PastGame) string {
+	reason := func(s string) string {
+		if g.Reason == "" {
+			return s
 		}
-		return fmt.Sprintf("Doubleheader! The %s go %d - %d", myTeamName, winCount, lossCount)
+		return fmt.Sprintf("%s (%s)", s, strings.ToLower(g.Reason))
+	}
+
+	mine, theirs := g.scores(rg.MyTeamId)
+	switch {
+	case g.PostponeReason != "" || g.State == GamePostponed:
+		return reason("postponed")
+	case g.State == GameCancelled:
+		return reason("cancelled")
+	case g.State == GameSuspended:
+		return reason("suspended")
+	case g.State == GameDelayed:
+		return reason("delayed")
+	case g.State == GameInProgress:
+		return fmt.Sprintf("still going %d-%d", mine.Score, theirs.Score)
+	case !g.Decided():
+		return "not started"
+	case mine.Score > theirs.Score:
+		return fmt.Sprintf("won %d-%d", mine.Score, theirs.Score)
+	case mine.Score < theirs.Score:
+		return fmt.Sprintf("lost %d-%d", mine.Score, theirs.Score)
 	default:
-		slog.Warn("Failed to generateHeadline, too many games played", slog.Int("pastGamesCount", len(pastGames)))
-		return "error"
+		return fmt.Sprintf("tied %d-%d", mine.Score, theirs.Score)
+	}
+}
+
+func (rg *ReportGenerator) gameHeadline(g PastGame, myTeamName string) string {
+	switch {
+	case g.PostponeReason != "":
+		return fmt.Sprintf("Game was postponed due to %s", g.PostponeReason)
+	case g.State == GamePostponed:
+		return "Game was postponed"
+	case g.State == GameCancelled:
+		return "Game was cancelled" + dueTo(g.Reason)
+	case g.State == GameSuspended && g.ResumesAt != "":
+		return fmt.Sprintf("Game suspended%s, resumes %s", dueTo(g.Reason), g.ResumesAt)
+	case g.State == GameSuspended:
+		return "Game was suspended" + dueTo(g.Reason)
+	case g.State == GameDelayed:
+		return "Game is delayed" + dueTo(g.Reason)
+	case g.State == GameInProgress:
+		return fmt.Sprintf("The %s are still playing", myTeamName)
+	case !g.Decided():
+		return fmt.Sprintf("The %s haven't played yet", myTeamName)
+	}
+
+	var headline string
+	if g.W.Score == g.L.Score {
+		headline = fmt.Sprintf("The %s tie, %d to %d", myTeamName, g.W.Score, g.L.Score)
+	} else if g.W.Team.Id == rg.MyTeamId {
+		headline = fmt.Sprintf("The %s win! %d to %d", myTeamName, g.W.Score, g.L.Score)
+	} else {
+		headline = fmt.Sprintf("The %s lose, %d to %d", myTeamName, g.L.Score, g.W.Score)
+	}
+
+	if g.State == GameCompletedEarly {
+		headline += ", called early" + dueTo(g.Reason)
 	}
+	if g.ResumedFrom != "" {
+		headline += " in a game resumed from " + g.ResumedFrom
+	}
+
+	return headline
+}
+
+func dueTo(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return " due to " + reason
+}
+
+// UpdateCondensedGames fills in the condensed games that hadn't been
+// published yet when the report was generated, and reports whether it found any
+func (rg *ReportGenerator) UpdateCondensedGames(r Report) (Report, bool) {
+	pastGames := make([]PastGame, len(r.Yesterday.PastGames))
+	copy(pastGames, r.Yesterday.PastGames)
+
+	var updated bool
+	for i, g := range pastGames {
+		// reports from before GamePk was saved can't be updated
+		if !g.Decided() || g.CondensedGameUrl != "" || g.GamePk == 0 {
+			continue
+		}
+
+		u, err := rg.fetchCondensedGame(g.GamePk)
+		if err != nil {
+			slog.Info(
+				"Condensed game still missing",
+				slog.Int("gamePk", g.GamePk),
+				slog.String("err", err.Error()),
+			)
+			continue
+		}
+
+		pastGames[i].CondensedGameUrl = u
+		updated = true
+	}
+
+	r.Yesterday.PastGames = pastGames
+	return r, updated
 }
 
 // logs errors
 func (rg *ReportGenerator) fetchCondensedGame(gamePk int) (string, error) {
 
-	c, err := rg.mc.FetchContent(gamePk)
+	c, err := rg.src.FetchContent(gamePk)
 	if err != nil {
 		return "", err
 	}
@@ -335,13 +677,13 @@ func (rg *ReportGenerator) fetchCondensedGame(gamePk int) (string, error) {
 
 func (rg *ReportGenerator) fetchLinescore(gamePk, homeId, awayId int) (Linescore, error) {
 
-	l, err := rg.mc.FetchLinescore(gamePk)
+	l, err := rg.src.FetchLinescore(gamePk)
 	if err != nil {
 		return Linescore{}, err
 	}
 
 	homeLinescore := LinescoreTeam{
-		Abbr:    rg.mc.AllTeams[homeId].Abbreviation,
+		Abbr:    rg.team(homeId).Abbreviation,
 		Innings: []int{},
 		Runs:    l.Teams.Home.Runs,
 		Hits:    l.Teams.Home.Hits,
@@ -349,7 +691,7 @@ func (rg *ReportGenerator) fetchLinescore(gamePk, homeId, awayId int) (Linescore
 	}
 
 	awayLinescore := LinescoreTeam{
-		Abbr:    rg.mc.AllTeams[awayId].Abbreviation,
+		Abbr:    rg.team(awayId).Abbreviation,
 		Innings: []int{},
 		Runs:    l.Teams.Away.Runs,
 		Hits:    l.Teams.Away.Hits,
@@ -361,7 +703,7 @@ func (rg *ReportGenerator) fetchLinescore(gamePk, homeId, awayId int) (Linescore
 		awayLinescore.Innings = append(awayLinescore.Innings, i.Away.Runs)
 	}
 
-	if l.IsTopInning {
+	if l.IsTopInning && len(homeLinescore.Innings) > 0 {
 		// negative numbers are rendered as x
 		homeLinescore.Innings[len(homeLinescore.Innings)-1] = -1
 	}
@@ -371,3 +713,238 @@ func (rg *ReportGenerator) fetchLinescore(gamePk, homeId, awayId int) (Linescore
 		Away: awayLinescore,
 	}, nil
 }
+
+// Incomplete is true when yesterday's games are missing something MLB
+// tends to publish later, like the condensed game, which often shows up
+// hours after the final out
+func (r Report) Incomplete() bool {
+	for _, g := range r.Yesterday.PastGames {
+		if !g.Decided() {
+			continue
+		}
+
+		if !g.HasLinescore || g.CondensedGameUrl == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ItemKey is Key, plus the revision once there is one
+func (r Report) ItemKey() string {
+	if r.Revision == 0 {
+		return r.Key()
+	}
+	return r.Key() + "-" + strconv.Itoa(r.Revision)
+}
+
+// Key identifies the report by the day it was generated for,
+// it is the basis for the stable item ids in the feed
+func (r Report) Key() string {
+	return r.When.Format(BaseballTheaterTimeFormat)
+}
+
+func (rg *ReportGenerator) fetchScoringPlays(gamePk, homeId, awayId int) ([]ScoringPlay, error) {
+	p, err := rg.src.FetchPlayByPlay(gamePk)
+	if err != nil {
+		return nil, err
+	}
+
+	homeAbbr := rg.team(homeId).Abbreviation
+	awayAbbr := rg.team(awayId).Abbreviation
+
+	scoringPlays := make([]ScoringPlay, 0)
+	for _, play := range p.FindScoringPlays() {
+		half := "Top"
+		if play.About.HalfInning == "bottom" {
+			half = "Bot"
+		}
+
+		scoringPlays = append(scoringPlays, ScoringPlay{
+			Inning:      fmt.Sprintf("%s %s", half, mlb.Ordinal(play.About.Inning)),
+			Description: play.Result.Description,
+			Score: fmt.Sprintf("%s %d, %s %d",
+				awayAbbr, play.Result.AwayScore,
+				homeAbbr, play.Result.HomeScore,
+			),
+		})
+	}
+
+	return scoringPlays, nil
+}
+
+// topHitters is how many of my team's hitters make it into the boxscore
+const topHitters = 3
+
+func (rg *ReportGenerator) fetchBoxscore(gamePk int) (Boxscore, error) {
+	b, err := rg.src.FetchBoxscore(gamePk)
+	if err != nil {
+		return Boxscore{}, err
+	}
+
+	teams := []mlb.BoxscoreTeam{b.Teams.Away, b.Teams.Home}
+
+	var starters []PitchingLine
+	for _, t := range teams {
+		if len(t.Pitchers) == 0 {
+			return Boxscore{}, errors.New("Failed to find starting pitchers")
+		}
+
+		p, found := t.FindPlayer(t.Pitchers[0])
+		if !found {
+			return Boxscore{}, errors.New("Failed to find starting pitcher")
+		}
+
+		starters = append(starters, rg.pitchingLine(p, t.Team.Id))
+	}
+
+	// the note looks like (W, 5-2), so sort by the letter
+	var decisions []PitchingLine
+	for _, prefix := range []string{"(W", "(L", "(S"} {
+		for _, t := range teams {
+			for _, id := range t.Pitchers {
+				p, found := t.FindPlayer(id)
+				if found && strings.HasPrefix(p.Stats.Pitching.Note, prefix) {
+					decisions = append(decisions, rg.pitchingLine(p, t.Team.Id))
+				}
+			}
+		}
+	}
+
+	myTeam := b.Teams.Home
+	if b.Teams.Away.Team.Id == rg.MyTeamId {
+		myTeam = b.Teams.Away
+	}
+
+	var hitters []BattingLine
+	for _, id := range myTeam.Batters {
+		p, found := myTeam.FindPlayer(id)
+		if !found {
+			continue
+		}
+
+		s := p.Stats.Batting
+		if s.Hits == 0 && s.Rbi == 0 {
+			continue
+		}
+
+		hitters = append(hitters, BattingLine{
+			Name:        p.Person.FullName,
+			AtBats:      s.AtBats,
+			Runs:        s.Runs,
+			Hits:        s.Hits,
+			HomeRuns:    s.HomeRuns,
+			Rbi:         s.Rbi,
+			BaseOnBalls: s.BaseOnBalls,
+		})
+	}
+
+	sort.SliceStable(hitters, func(i, j int) bool {
+		x, y := hitters[i], hitters[j]
+		if x.Hits != y.Hits {
+			return x.Hits > y.Hits
+		}
+		if x.HomeRuns != y.HomeRuns {
+			return x.HomeRuns > y.HomeRuns
+		}
+		return x.Rbi > y.Rbi
+	})
+	if len(hitters) > topHitters {
+		hitters = hitters[:topHitters]
+	}
+
+	return Boxscore{
+		Starters:   starters,
+		Decisions:  decisions,
+		TopHitters: hitters,
+	}, nil
+}
+
+func (rg *ReportGenerator) pitchingLine(p mlb.BoxscorePlayer, teamId int) PitchingLine {
+	s := p.Stats.Pitching
+	return PitchingLine{
+		Name:           p.Person.FullName,
+		Abbr:           rg.team(teamId).Abbreviation,
+		Note:           s.Note,
+		InningsPitched: s.InningsPitched,
+		Hits:           s.Hits,
+		Runs:           s.Runs,
+		EarnedRuns:     s.EarnedRuns,
+		BaseOnBalls:    s.BaseOnBalls,
+		StrikeOuts:     s.StrikeOuts,
+	}
+}
+
+// wildCardRows is how many teams are shown in the wild card race,
+// the three that are in plus the three closest to getting in
+const wildCardRows = 6
+
+func (rg *ReportGenerator) fetchStandings(today time.Time) (Standings, error) {
+	myTeam := rg.team(rg.MyTeamId)
+
+	s, err := rg.src.FetchStandings(myTeam.League.Id, today.Year(), "regularSeason")
+	if err != nil {
+		return Standings{}, err
+	}
+
+	division := StandingsTable{
+		Name: myTeam.Division.Name,
+		Rows: []StandingsRow{},
+	}
+	for _, r := range s.Records {
+		if r.Division.Id != myTeam.Division.Id {
+			continue
+		}
+
+		for _, tr := range r.TeamRecords {
+			division.Rows = append(division.Rows, rg.standingsRow(tr, tr.GamesBack))
+		}
+	}
+
+	if len(division.Rows) == 0 {
+		return Standings{}, errors.New("Failed to find division standings")
+	}
+
+	s, err = rg.src.FetchStandings(myTeam.League.Id, today.Year(), "wildCard")
+	if err != nil {
+		return Standings{}, err
+	}
+
+	wildCard := StandingsTable{
+		Name: myTeam.League.Name + " Wild Card",
+		Rows: []StandingsRow{},
+	}
+	for _, r := range s.Records {
+		for i, tr := range r.TeamRecords {
+			// always show my team, even when far behind
+			if i < wildCardRows || tr.Team.Id == rg.MyTeamId {
+				wildCard.Rows = append(wildCard.Rows, rg.standingsRow(tr, tr.WildCardGamesBack))
+			}
+		}
+	}
+
+	return Standings{
+		Division: division,
+		WildCard: wildCard,
+	}, nil
+}
+
+func (rg *ReportGenerator) standingsRow(tr mlb.TeamRecord, gamesBack string) StandingsRow {
+	var lastTen string
+	if sr, found := tr.FindSplitRecord("lastTen"); found {
+		lastTen = fmt.Sprintf("%d-%d", sr.Wins, sr.Losses)
+	}
+
+	return StandingsRow{
+		Abbr:            rg.team(tr.Team.Id).Abbreviation,
+		Wins:            tr.LeagueRecord.Wins,
+		Losses:          tr.LeagueRecord.Losses,
+		Pct:             tr.LeagueRecord.Pct,
+		GamesBack:       gamesBack,
+		LastTen:         lastTen,
+		Streak:          tr.Streak.StreakCode,
+		RunDifferential: tr.RunDifferential,
+		IsMyTeam:        tr.Team.Id == rg.MyTeamId,
+	}
+}
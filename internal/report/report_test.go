@@ -0,0 +1,116 @@
+package report
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/mlb/mlbtest"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+// scenarios run the whole pipeline against fixtures in test/data, with the
+// statsapi responses replayed and the day of the report fixed
+var scenarios = []struct {
+	name     string
+	fixtures string
+	team     string
+	today    string
+}{
+	// win with linescore, boxscore, scoring plays, condensed game and standings
+	{name: "win", fixtures: "scenarios/win", team: "BAL", today: "2022-05-28"},
+	{name: "loss", fixtures: ".", team: "BAL", today: "2022-05-30"},
+	// a spring training game that ended 3-3
+	{name: "tie", fixtures: "scenarios/tie", team: "DET", today: "2023-03-20"},
+	{name: "postponed", fixtures: ".", team: "COL", today: "2022-05-28"},
+	// rained out in the 6th, to be finished the next day. hand made rather than recorded,
+	// with a made up gamePk so a recording never overwrites or clashes with it
+	{name: "suspended", fixtures: "scenarios/suspended", team: "BAL", today: "2022-05-28"},
+	// the orioles lost game 1 of the 2023 ALDS
+	{name: "postseason", fixtures: "scenarios/postseason", team: "BAL", today: "2023-10-08"},
+	// and were swept in game 3
+	{name: "eliminated", fixtures: "scenarios/eliminated", team: "BAL", today: "2023-10-11"},
+	// but the report keeps following the astros
+	{name: "out", fixtures: "scenarios/out", team: "BAL", today: "2023-10-12"},
+	{name: "doubleheader", fixtures: ".", team: "BAL", today: "2022-05-29"},
+	{name: "off-day", fixtures: ".", team: "BAL", today: "2022-04-15"},
+	{name: "offseason", fixtures: ".", team: "BAL", today: "2022-12-01"},
+	// a week of signings, a trade and a dfa, hand made rather than recorded
+	{name: "digest", fixtures: "scenarios/digest", team: "BAL", today: "2022-12-13"},
+}
+
+func TestGolden(t *testing.T) {
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) {
+			fixtures := os.DirFS(filepath.Join("..", "..", "test", "data", sc.fixtures))
+			mc, err := mlb.NewMlbClient(mlb.WithTransport(mlbtest.Replay(fixtures)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			team, ok := mc.FindTeam(sc.team)
+			if !ok {
+				t.Fatalf("no team %s", sc.team)
+			}
+
+			today, err := time.Parse(time.DateOnly, sc.today)
+			if err != nil {
+				t.Fatal(err)
+			}
+			today = today.Add(7 * time.Hour)
+
+			rg := NewReportGenerator(team.Id, mc, time.UTC)
+			rg.BoxScore = true
+
+			r, err := rg.GenerateReport(today)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rendered, err := rg.Render(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, sc.name+".rss.html", rendered)
+
+			web, err := rg.RenderWeb(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, sc.name+".web.html", web)
+		})
+	}
+}
+
+// golden compares got to testdata/name, or overwrites it with -update
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+
+	p := filepath.Join("testdata", name)
+	if *update {
+		err := os.MkdirAll("testdata", 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(p, []byte(got), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("%v, run go test ./internal/report -update to create it", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("%s does not match, run go test ./internal/report -update and check the diff\n--- got\n%s", p, got)
+	}
+}
@@ -0,0 +1,186 @@
+// Package reporttest is an in memory report.Source, for generating
+// reports out of hand made games instead of statsapi responses
+package reporttest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// ErrNotFound is returned for anything the Source wasn't given
+var ErrNotFound = errors.New("Not found")
+
+// Source serves whatever was put into it. Everything but Teams is optional,
+// missing content, linescores and so on are ErrNotFound, just like a game
+// that statsapi has nothing for yet
+type Source struct {
+	Teams      map[int]mlb.Team
+	Dates      []mlb.Date
+	Content    map[int]mlb.Content
+	Linescores map[int]mlb.Linescore
+	Boxscores  map[int]mlb.Boxscore
+	PlayByPlay map[int]mlb.PlayByPlay
+	// Standings are by standings type, like regularSeason or wildCard
+	Standings map[string]mlb.Standings
+	// Seasons are by year
+	Seasons map[int]mlb.Season
+	// Transactions are by team, FetchTransactions keeps the ones in range
+	Transactions map[int][]mlb.Transaction
+	// Rosters are by team, the same whatever the date
+	Rosters map[int]mlb.Roster
+}
+
+// NewSource is an empty Source that knows every real team
+func NewSource() (*Source, error) {
+	teams, err := mlb.LoadTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Source{
+		Teams:        teams,
+		Content:      make(map[int]mlb.Content),
+		Linescores:   make(map[int]mlb.Linescore),
+		Boxscores:    make(map[int]mlb.Boxscore),
+		PlayByPlay:   make(map[int]mlb.PlayByPlay),
+		Standings:    make(map[string]mlb.Standings),
+		Seasons:      make(map[int]mlb.Season),
+		Transactions: make(map[int][]mlb.Transaction),
+		Rosters:      make(map[int]mlb.Roster),
+	}, nil
+}
+
+// AddGame schedules g on the day of date (yyyy-mm-dd)
+func (s *Source) AddGame(date string, g mlb.Game) {
+	for i, d := range s.Dates {
+		if d.Date == date {
+			s.Dates[i].Games = append(s.Dates[i].Games, g)
+			return
+		}
+	}
+
+	s.Dates = append(s.Dates, mlb.Date{
+		Date:  date,
+		Games: []mlb.Game{g},
+	})
+}
+
+// FetchSchedule keeps the dates from start to end, and the games of teamId
+func (s *Source) FetchSchedule(start, end time.Time, teamId int) (mlb.Schedule, error) {
+	startDate := start.Format(time.DateOnly)
+	endDate := end.Format(time.DateOnly)
+
+	dates := make([]mlb.Date, 0)
+	for _, d := range s.Dates {
+		if d.Date < startDate || d.Date > endDate {
+			continue
+		}
+
+		games := make([]mlb.Game, 0)
+		for _, g := range d.Games {
+			if g.Teams.Home.Team.Id == teamId || g.Teams.Away.Team.Id == teamId {
+				games = append(games, g)
+			}
+		}
+
+		// like statsapi, days without games are left out
+		if len(games) > 0 {
+			dates = append(dates, mlb.Date{
+				Date:  d.Date,
+				Games: games,
+			})
+		}
+	}
+
+	return mlb.Schedule{Dates: dates}, nil
+}
+
+// FetchPostseasonSchedule keeps the postseason games of the season, for every team
+func (s *Source) FetchPostseasonSchedule(season int) (mlb.Schedule, error) {
+	prefix := strconv.Itoa(season) + "-"
+
+	dates := make([]mlb.Date, 0)
+	for _, d := range s.Dates {
+		if !strings.HasPrefix(d.Date, prefix) {
+			continue
+		}
+
+		games := make([]mlb.Game, 0)
+		for _, g := range d.Games {
+			if g.IsPostseason() {
+				games = append(games, g)
+			}
+		}
+
+		if len(games) > 0 {
+			dates = append(dates, mlb.Date{
+				Date:  d.Date,
+				Games: games,
+			})
+		}
+	}
+
+	return mlb.Schedule{Dates: dates}, nil
+}
+
+func (s *Source) FetchContent(gamePk int) (mlb.Content, error) {
+	return lookup(s.Content, gamePk)
+}
+
+func (s *Source) FetchLinescore(gamePk int) (mlb.Linescore, error) {
+	return lookup(s.Linescores, gamePk)
+}
+
+func (s *Source) FetchBoxscore(gamePk int) (mlb.Boxscore, error) {
+	return lookup(s.Boxscores, gamePk)
+}
+
+func (s *Source) FetchPlayByPlay(gamePk int) (mlb.PlayByPlay, error) {
+	return lookup(s.PlayByPlay, gamePk)
+}
+
+func (s *Source) FetchStandings(leagueId, season int, standingsType string) (mlb.Standings, error) {
+	return lookup(s.Standings, standingsType)
+}
+
+func (s *Source) FetchSeason(season int) (mlb.Season, error) {
+	return lookup(s.Seasons, season)
+}
+
+func (s *Source) FetchTransactions(teamId int, start, end time.Time) (mlb.Transactions, error) {
+	startDate := start.Format(time.DateOnly)
+	endDate := end.Format(time.DateOnly)
+
+	transactions := make([]mlb.Transaction, 0)
+	for _, t := range s.Transactions[teamId] {
+		if t.Date >= startDate && t.Date <= endDate {
+			transactions = append(transactions, t)
+		}
+	}
+
+	return mlb.Transactions{Transactions: transactions}, nil
+}
+
+func (s *Source) FetchRoster(teamId int, date time.Time) (mlb.Roster, error) {
+	return lookup(s.Rosters, teamId)
+}
+
+func (s *Source) Team(id int) (mlb.Team, bool) {
+	t, ok := s.Teams[id]
+	return t, ok
+}
+
+func lookup[K comparable, V any](m map[K]V, k K) (V, error) {
+	v, ok := m[k]
+	if !ok {
+		var zero V
+		return zero, fmt.Errorf("%w: %v", ErrNotFound, k)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// fetchRoster is my team's 40 man roster, kept in the report so that
+// the next one can tell what changed in between
+func (rg *ReportGenerator) fetchRoster(today time.Time) ([]RosterPlayer, error) {
+	r, err := rg.src.FetchRoster(rg.MyTeamId, today)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]RosterPlayer, 0, len(r.Roster))
+	for _, e := range r.Roster {
+		players = append(players, RosterPlayer{
+			Id:                e.Person.Id,
+			Name:              e.Person.FullName,
+			Position:          e.Position.Abbreviation,
+			Status:            e.Status.Code,
+			StatusDescription: e.Status.Description,
+		})
+	}
+
+	return players, nil
+}
+
+// RosterMoves compares the roster of r to the one of previous, the report
+// before it, and fills in the moves made in between. Reports from before
+// rosters were kept around have nothing to compare to
+func (rg *ReportGenerator) RosterMoves(r, previous Report) Report {
+	if len(r.Roster) == 0 || len(previous.Roster) == 0 {
+		return r
+	}
+
+	before := make(map[int]RosterPlayer)
+	for _, p := range previous.Roster {
+		before[p.Id] = p
+	}
+
+	var moves []RosterMove
+	kinds := make(map[int]moveKind)
+	after := make(map[int]bool)
+	for _, p := range r.Roster {
+		after[p.Id] = true
+
+		b, ok := before[p.Id]
+		if k, m := rosterMove(b, ok, p); m != "" {
+			kinds[p.Id] = k
+			moves = append(moves, RosterMove{PlayerId: p.Id, Name: p.Name, Position: p.Position, Move: m})
+		}
+	}
+	for _, p := range previous.Roster {
+		if !after[p.Id] {
+			kinds[p.Id] = moveRemoved
+			moves = append(moves, RosterMove{PlayerId: p.Id, Name: p.Name, Position: p.Position, Move: "Removed from the 40-man roster"})
+		}
+	}
+
+	if len(moves) == 0 {
+		return r
+	}
+
+	// the official word on each move, which has things like the injury. A player can
+	// have a few transactions in between, like a call up and then an IL placement,
+	// so only the newest one of the kind that explains the move counts
+	ts, err := rg.src.FetchTransactions(rg.MyTeamId, previous.When, r.When)
+	if err != nil {
+		slog.Warn("Failed to fetch transactions", slog.String("err", err.Error()))
+	}
+	details := make(map[int]mlb.Transaction)
+	for _, t := range ts.Transactions {
+		k, ok := kinds[t.Person.Id]
+		if !ok || !contains(moveTransactions[k], t.TypeCode) {
+			continue
+		}
+		if t.ToTeam.Id != rg.MyTeamId && t.FromTeam.Id != rg.MyTeamId {
+			continue
+		}
+		if d, ok := details[t.Person.Id]; ok && d.Date > t.Date {
+			continue
+		}
+		details[t.Person.Id] = t
+	}
+
+	for i := range moves {
+		moves[i].Details = details[moves[i].PlayerId].Description
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		ki, kj := kinds[moves[i].PlayerId], kinds[moves[j].PlayerId]
+		if ki != kj {
+			return ki < kj
+		}
+		return moves[i].Name < moves[j].Name
+	})
+
+	r.HasRosterMoves = true
+	r.RosterMoves = moves
+	return r
+}
+
+// moveKind is the kind of roster move, in the order they are listed
+type moveKind int
+
+const (
+	moveAdded moveKind = iota
+	moveRemoved
+	moveCalledUp
+	moveOptioned
+	movePlaced
+	moveMoved
+	moveActivated
+	moveOther
+)
+
+// moveTransactions are the transaction type codes that can explain each kind of move
+var moveTransactions = map[moveKind][]string{
+	moveAdded: {mlb.TransactionSignedFreeAgent, mlb.TransactionSigned, mlb.TransactionTrade,
+		mlb.TransactionClaimed, mlb.TransactionSelected},
+	moveRemoved: {mlb.TransactionDesignated, mlb.TransactionTrade, mlb.TransactionClaimed,
+		mlb.TransactionOutrighted, mlb.TransactionReleased},
+	moveCalledUp:  {mlb.TransactionRecalled, mlb.TransactionSelected},
+	moveOptioned:  {mlb.TransactionOptioned},
+	movePlaced:    {mlb.TransactionStatusChange},
+	moveMoved:     {mlb.TransactionStatusChange},
+	moveActivated: {mlb.TransactionStatusChange},
+	moveOther:     {mlb.TransactionStatusChange},
+}
+
+// rosterMove is what happened to a player between two rosters,
+// or nothing when their status is the same
+func rosterMove(before RosterPlayer, wasOnRoster bool, after RosterPlayer) (moveKind, string) {
+	switch {
+	case !wasOnRoster:
+		return moveAdded, "Added to the 40-man roster"
+	case before.Status == after.Status:
+		return moveOther, ""
+	case injuredList(after.Status) != "" && injuredList(before.Status) != "":
+		return moveMoved, "Moved to the " + injuredList(after.Status)
+	case injuredList(after.Status) != "":
+		return movePlaced, "Placed on the " + injuredList(after.Status)
+	case injuredList(before.Status) != "" && after.Status == "A":
+		return moveActivated, "Activated from the " + injuredList(before.Status)
+	case before.Status == "A" && after.Status == "MIN":
+		return moveOptioned, "Optioned to the minors"
+	case after.Status == "A":
+		return moveCalledUp, "Called up"
+	default:
+		return moveOther, "Now " + strings.ToLower(after.StatusDescription)
+	}
+}
+
+// injuredList turns a status like D15 into "15-day IL", and anything else into ""
+func injuredList(status string) string {
+	days, ok := strings.CutPrefix(status, "D")
+	if !ok || days == "" || strings.Trim(days, "0123456789") != "" {
+		return ""
+	}
+	return days + "-day IL"
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+func rosterEntry(id int, name, position, status, description string) mlb.RosterEntry {
+	return mlb.RosterEntry{
+		Person:   mlb.Person{Id: id, FullName: name},
+		Position: mlb.Position{Abbreviation: position},
+		Status:   mlb.RosterStatus{Code: status, Description: description},
+	}
+}
+
+func TestRosterMoves(t *testing.T) {
+	src, err := reporttest.NewSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rg := NewReportGenerator(bal, src, time.UTC)
+	yesterday := time.Date(2023, 6, 1, 7, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+
+	src.Rosters[bal] = mlb.Roster{Roster: []mlb.RosterEntry{
+		rosterEntry(1, "Kyle Bradish", "P", "A", "Active"),
+		rosterEntry(2, "Adley Rutschman", "C", "A", "Active"),
+		rosterEntry(3, "Jorge Mateo", "SS", "A", "Active"),
+		rosterEntry(4, "John Means", "P", "D60", "Injured 60-Day"),
+		rosterEntry(5, "Ryan Mountcastle", "1B", "A", "Active"),
+		rosterEntry(6, "Gunnar Henderson", "3B", "A", "Active"),
+	}}
+	before, err := rg.GenerateReport(yesterday)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src.Rosters[bal] = mlb.Roster{Roster: []mlb.RosterEntry{
+		rosterEntry(1, "Kyle Bradish", "P", "D15", "Injured 15-Day"),
+		rosterEntry(2, "Adley Rutschman", "C", "A", "Active"),
+		rosterEntry(3, "Jorge Mateo", "SS", "MIN", "Reassigned to Minors"),
+		rosterEntry(4, "John Means", "P", "A", "Active"),
+		rosterEntry(6, "Gunnar Henderson", "3B", "PL", "Paternity List"),
+		rosterEntry(7, "Jordan Westburg", "2B", "A", "Active"),
+	}}
+	src.Transactions[bal] = []mlb.Transaction{
+		{
+			Person:      mlb.Person{Id: 1, FullName: "Kyle Bradish"},
+			ToTeam:      mlb.TeamSummary{Id: bal},
+			Date:        "2023-06-01",
+			TypeCode:    "SC",
+			Description: "Baltimore Orioles placed RHP Kyle Bradish on the 15-day injured list. Right foot contusion.",
+		},
+		// newer, but nothing to do with being on the IL
+		{
+			Person:      mlb.Person{Id: 1, FullName: "Kyle Bradish"},
+			ToTeam:      mlb.TeamSummary{Id: bal},
+			Date:        "2023-06-02",
+			TypeCode:    "NUM",
+			Description: "RHP Kyle Bradish changed number to 39.",
+		},
+		{
+			Person:      mlb.Person{Id: 3, FullName: "Jorge Mateo"},
+			ToTeam:      mlb.TeamSummary{Id: bal},
+			Date:        "2023-06-01",
+			TypeCode:    "OPT",
+			Description: "Baltimore Orioles optioned SS Jorge Mateo to Norfolk Tides.",
+		},
+	}
+	after, err := rg.GenerateReport(today)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rg.RosterMoves(after, before)
+	if !r.HasRosterMoves {
+		t.Fatal("no roster moves")
+	}
+
+	want := []RosterMove{
+		{PlayerId: 7, Name: "Jordan Westburg", Position: "2B", Move: "Added to the 40-man roster"},
+		{PlayerId: 5, Name: "Ryan Mountcastle", Position: "1B", Move: "Removed from the 40-man roster"},
+		{
+			PlayerId: 3, Name: "Jorge Mateo", Position: "SS", Move: "Optioned to the minors",
+			Details: "Baltimore Orioles optioned SS Jorge Mateo to Norfolk Tides.",
+		},
+		{
+			PlayerId: 1, Name: "Kyle Bradish", Position: "P", Move: "Placed on the 15-day IL",
+			Details: "Baltimore Orioles placed RHP Kyle Bradish on the 15-day injured list. Right foot contusion.",
+		},
+		{PlayerId: 4, Name: "John Means", Position: "P", Move: "Activated from the 60-day IL"},
+		{PlayerId: 6, Name: "Gunnar Henderson", Position: "3B", Move: "Now paternity list"},
+	}
+	if len(r.RosterMoves) != len(want) {
+		t.Fatalf("got %+v, want %+v", r.RosterMoves, want)
+	}
+	for i := range want {
+		if r.RosterMoves[i] != want[i] {
+			t.Errorf("move %d is %+v, want %+v", i, r.RosterMoves[i], want[i])
+		}
+	}
+
+	rendered, err := rg.Render(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rendered, "<li>Placed on the 15-day IL: P Kyle Bradish<br><small>") {
+		t.Errorf("roster moves are missing from the report\n%s", rendered)
+	}
+
+	// nothing to compare to
+	if r := rg.RosterMoves(after, Report{}); r.HasRosterMoves {
+		t.Errorf("got moves without a previous roster %+v", r.RosterMoves)
+	}
+}
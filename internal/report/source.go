@@ -0,0 +1,25 @@
+package report
+
+import (
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// Source is everything a report is made from. mlb.MlbClient is the real
+// thing, and reporttest.Source is a fake for running without statsapi
+type Source interface {
+	FetchSchedule(start, end time.Time, teamId int) (mlb.Schedule, error)
+	FetchPostseasonSchedule(season int) (mlb.Schedule, error)
+	FetchContent(gamePk int) (mlb.Content, error)
+	FetchLinescore(gamePk int) (mlb.Linescore, error)
+	FetchBoxscore(gamePk int) (mlb.Boxscore, error)
+	FetchPlayByPlay(gamePk int) (mlb.PlayByPlay, error)
+	FetchStandings(leagueId, season int, standingsType string) (mlb.Standings, error)
+	FetchSeason(season int) (mlb.Season, error)
+	FetchTransactions(teamId int, start, end time.Time) (mlb.Transactions, error)
+	FetchRoster(teamId int, date time.Time) (mlb.Roster, error)
+	Team(id int) (mlb.Team, bool)
+}
+
+var _ Source = (*mlb.MlbClient)(nil)
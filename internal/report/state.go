@@ -0,0 +1,130 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/mlb"
+)
+
+// GameState is what became of a game, which is more than just who won
+type GameState string
+
+const (
+	GameFinal GameState = "final"
+	// GameCompletedEarly is a final score, but called before nine innings, usually for rain
+	GameCompletedEarly GameState = "completedEarly"
+	GamePostponed      GameState = "postponed"
+	GameCancelled      GameState = "cancelled"
+	// GameSuspended is stopped partway, to be resumed on a later day
+	GameSuspended  GameState = "suspended"
+	GameDelayed    GameState = "delayed"
+	GameInProgress GameState = "inProgress"
+	GameNotStarted GameState = "notStarted"
+)
+
+// gameState reads the state from the coded state, but the detailed state is
+// checked first because some states share a code, like "Completed Early" and "Final"
+//
+// coded states seen so far: S scheduled, P pre-game, I in progress, O game over,
+// F final, D postponed, C cancelled, T and U suspended
+func gameState(s mlb.Status) GameState {
+	switch {
+	case s.DetailedState == "Postponed" || s.CodedGameState == "D":
+		return GamePostponed
+	case s.DetailedState == "Cancelled" || s.CodedGameState == "C":
+		return GameCancelled
+	case strings.HasPrefix(s.DetailedState, "Suspended") || s.CodedGameState == "T" || s.CodedGameState == "U":
+		return GameSuspended
+	case strings.HasPrefix(s.DetailedState, "Completed Early"):
+		return GameCompletedEarly
+	case s.CodedGameState == "F" || s.CodedGameState == "O":
+		return GameFinal
+	case s.CodedGameState == "S" || s.CodedGameState == "P":
+		// includes "Delayed Start", there are no innings yet
+		return GameNotStarted
+	case strings.HasPrefix(s.DetailedState, "Delayed"):
+		return GameDelayed
+	case s.CodedGameState == "I" || s.AbstractGameState == "Live":
+		return GameInProgress
+	default:
+		return GameNotStarted
+	}
+}
+
+// Started is true when there are innings to show
+func (s GameState) Started() bool {
+	switch s {
+	case GameFinal, GameCompletedEarly, GameSuspended, GameDelayed, GameInProgress:
+		return true
+	default:
+		return false
+	}
+}
+
+// Decided is true for games with a final score
+func (p PastGame) Decided() bool {
+	switch p.State {
+	case GameFinal, GameCompletedEarly:
+		return true
+	case "":
+		// reports saved before State existed only knew final and postponed games
+		return p.PostponeReason == ""
+	default:
+		return false
+	}
+}
+
+// Away is the away team, whatever the state of the game
+func (p PastGame) Away() mlb.GameTeam {
+	if p.IsWinnerHome {
+		return p.L
+	}
+	return p.W
+}
+
+// Home is the home team, whatever the state of the game
+func (p PastGame) Home() mlb.GameTeam {
+	if p.IsWinnerHome {
+		return p.W
+	}
+	return p.L
+}
+
+// scores splits the game into my team and the other team
+func (p PastGame) scores(myTeamId int) (mine, theirs mlb.GameTeam) {
+	if p.L.Team.Id == myTeamId {
+		return p.L, p.W
+	}
+	return p.W, p.L
+}
+
+// stateReason is the part after the colon, like Rain in "Suspended: Rain"
+func stateReason(s mlb.Status) string {
+	if s.Reason != "" {
+		return s.Reason
+	}
+
+	_, reason, _ := strings.Cut(s.DetailedState, ": ")
+	return reason
+}
+
+// resumesAt is like "today at 12:05", "tomorrow at 12:05" or "Tuesday 5/31 at 12:05"
+func resumesAt(resume, today time.Time, loc *time.Location) string {
+	return fmt.Sprintf("%s at %s", relativeDay(resume, today, loc), resume.In(loc).Format("15:04"))
+}
+
+// relativeDay is "today", "tomorrow", or otherwise like "Tuesday 5/31"
+func relativeDay(t, today time.Time, loc *time.Location) string {
+	y, m, d := today.In(loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	switch t.In(loc).Format(time.DateOnly) {
+	case day.Format(time.DateOnly):
+		return "today"
+	case day.AddDate(0, 0, 1).Format(time.DateOnly):
+		return "tomorrow"
+	default:
+		return t.In(loc).Format("Monday 1/2")
+	}
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
+	"github.com/0queue/mlb-rss/internal/mlb"
+	"github.com/0queue/mlb-rss/internal/report/reporttest"
+)
+
+func TestGameState(t *testing.T) {
+	tests := []struct {
+		coded, detailed string
+		want            GameState
+	}{
+		{"S", "Scheduled", GameNotStarted},
+		{"P", "Pre-Game", GameNotStarted},
+		{"P", "Delayed Start: Rain", GameNotStarted},
+		{"S", "Delayed Start", GameNotStarted},
+		{"I", "Delayed: Rain", GameDelayed},
+		{"I", "In Progress", GameInProgress},
+		{"T", "Suspended: Rain", GameSuspended},
+		{"D", "Postponed", GamePostponed},
+		{"F", "Completed Early: Rain", GameCompletedEarly},
+		{"F", "Final", GameFinal},
+	}
+
+	for _, tt := range tests {
+		got := gameState(mlb.Status{CodedGameState: tt.coded, DetailedState: tt.detailed})
+		if got != tt.want {
+			t.Errorf("%s %q: got %q, want %q", tt.coded, tt.detailed, got, tt.want)
+		}
+	}
+}
+
+// statsapi has a linescore with no innings before the first pitch,
+// which must not take the whole report down
+func TestDelayedWithoutInnings(t *testing.T) {
+	tests := []struct {
+		name            string
+		coded, detailed string
+		want            GameState
+	}{
+		{"delayed start", "P", "Delayed Start: Rain", GameNotStarted},
+		{"delayed", "I", "Delayed: Rain", GameDelayed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := reporttest.NewSource()
+			if err != nil {
+				t.Fatal(err)
+			}
+			src.AddGame("2022-05-28", withStatus(finalGame(1, nyy, 0, bal, 0), tt.coded, tt.detailed))
+			src.Linescores[1] = mlb.Linescore{IsTopInning: true}
+
+			rg := NewReportGenerator(bal, src, time.UTC)
+			rg.Clock = clock.NewFake(time.Date(2022, 5, 29, 7, 0, 0, 0, time.UTC))
+
+			r, err := rg.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(r.Yesterday.PastGames) != 1 {
+				t.Fatalf("got %d games, want 1", len(r.Yesterday.PastGames))
+			}
+			if got := r.Yesterday.PastGames[0].State; got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			_, err = rg.Render(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
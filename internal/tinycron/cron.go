@@ -0,0 +1,236 @@
+package tinycron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a job runs next
+type Schedule interface {
+	// Next is the first run strictly after after, the zero time means never
+	Next(after time.Time) time.Time
+}
+
+// Cron is a standard 5 field cron expression: minute, hour, day of month,
+// month and day of week. Fields can be *, numbers, ranges (1-5), steps (*/15, 0-30/10)
+// and lists of those (1,15). Sunday is 0 or 7. Like in cron, when both day fields
+// are restricted a day matching either one is enough, and like in vixie cron a day
+// field starting with * (*/2 too) doesn't count as restricted
+type Cron struct {
+	spec     string
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	anyDay   bool
+	location *time.Location
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse reads a cron expression, evaluated in loc (time.Local if nil)
+func Parse(spec string, loc *time.Location) (*Cron, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q has %d fields, expected 5", spec, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, p := range parts {
+		b, err := parseField(p, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	// 7 is sunday too
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &Cron{
+		spec:     spec,
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		anyDay:   strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[4], "*"),
+		location: loc,
+	}, nil
+}
+
+// MustParse is Parse, but panics
+func MustParse(spec string, loc *time.Location) *Cron {
+	c, err := Parse(spec, loc)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c *Cron) String() string {
+	return c.spec
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("bad step %q in %s", stepStr, f.name)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+
+			var err error
+			lo, err = strconv.Atoi(loStr)
+			if err != nil {
+				return 0, fmt.Errorf("bad value %q in %s", loStr, f.name)
+			}
+
+			hi = lo
+			if isRange {
+				hi, err = strconv.Atoi(hiStr)
+				if err != nil {
+					return 0, fmt.Errorf("bad value %q in %s", hiStr, f.name)
+				}
+			} else if hasStep {
+				// 5/15 means from 5 to the end, every 15
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s must be between %d and %d, got %q", f.name, f.min, f.max, part)
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+// maxYears is how far to look ahead before giving up on something like February 30th
+const maxYears = 5
+
+// Next works on the wall clock of the Cron's location, so a DST jump doesn't
+// shift the time of day. A time skipped by a jump forward runs at the jump instead,
+// and a time repeated by a jump back only runs the first time around
+func (c *Cron) Next(after time.Time) time.Time {
+	after = after.In(c.location)
+
+	// start at the next whole minute
+	y, mo, d := after.Date()
+	h, mi := after.Hour(), after.Minute()+1
+
+	for y <= after.Year()+maxYears {
+		if mi > 59 {
+			mi = 0
+			h += 1
+		}
+		if h > 23 {
+			h = 0
+			d += 1
+		}
+		if d > daysIn(y, mo) {
+			d = 1
+			mo += 1
+		}
+		if mo > 12 {
+			mo = 1
+			y += 1
+		}
+
+		switch {
+		case c.month&(1<<uint(mo)) == 0:
+			mo, d, h, mi = mo+1, 1, 0, 0
+		case !c.matchesDay(y, mo, d):
+			d, h, mi = d+1, 0, 0
+		case c.hour&(1<<uint(h)) == 0:
+			h, mi = h+1, 0
+		case c.minute&(1<<uint(mi)) == 0:
+			mi += 1
+		default:
+			t := time.Date(y, mo, d, h, mi, 0, 0, c.location)
+			if t.Hour() != h || t.Minute() != mi {
+				t = c.jump(y, mo, d, h, mi)
+			}
+			if t.After(after) {
+				return t
+			}
+			// the first time around a jump back already ran
+			mi += 1
+		}
+	}
+
+	return time.Time{}
+}
+
+// jump finds the end of the DST gap that the wall clock h:mi is in. time.Date
+// makes something up for a wall clock that doesn't exist, so look for the first
+// minute after it that does
+func (c *Cron) jump(y int, mo time.Month, d, h, mi int) time.Time {
+	for skip := 1; skip <= 24*60; skip++ {
+		minutes := h*60 + mi + skip
+		t := time.Date(y, mo, d, 0, minutes, 0, 0, c.location)
+		if t.Hour() == minutes/60%24 && t.Minute() == minutes%60 {
+			return t
+		}
+	}
+
+	return time.Date(y, mo, d, h, mi, 0, 0, c.location)
+}
+
+func (c *Cron) matchesDay(y int, mo time.Month, d int) bool {
+	domMatch := c.dom&(1<<uint(d)) != 0
+	dowMatch := c.dow&(1<<uint(time.Date(y, mo, d, 12, 0, 0, 0, time.UTC).Weekday())) != 0
+	if c.anyDay {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func daysIn(y int, mo time.Month) int {
+	return time.Date(y, mo+1, 0, 12, 0, 0, 0, time.UTC).Day()
+}
+
+// Interval runs every d, counting from the last run
+type Interval time.Duration
+
+func (i Interval) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(i))
+}
+
+func (i Interval) String() string {
+	return "every " + time.Duration(i).String()
+}
@@ -0,0 +1,113 @@
+package tinycron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 7 * *",
+		"0 7 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	} {
+		if _, err := Parse(spec, time.UTC); err == nil {
+			t.Errorf("%q should not parse", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	date := func(y int, mo time.Month, d, h, mi, s int) time.Time {
+		return time.Date(y, mo, d, h, mi, s, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		// at the top of the hour, not at the minute and second it was started at
+		{"0 7 * * *", date(2023, 6, 1, 6, 20, 13), date(2023, 6, 1, 7, 0, 0)},
+		{"0 7 * * *", date(2023, 6, 1, 7, 0, 0), date(2023, 6, 2, 7, 0, 0)},
+		{"0 7 * * *", date(2023, 12, 31, 8, 0, 0), date(2024, 1, 1, 7, 0, 0)},
+		{"*/15 * * * *", date(2023, 6, 1, 6, 20, 13), date(2023, 6, 1, 6, 30, 0)},
+		{"5/20 * * * *", date(2023, 6, 1, 6, 46, 0), date(2023, 6, 1, 7, 5, 0)},
+		// friday evening to monday morning
+		{"*/15 9-17 * * 1-5", date(2023, 6, 2, 17, 50, 0), date(2023, 6, 5, 9, 0, 0)},
+		{"0 0 1,15 * *", date(2023, 6, 2, 0, 0, 0), date(2023, 6, 15, 0, 0, 0)},
+		// 7 is sunday too
+		{"0 12 * * 7", date(2023, 6, 1, 0, 0, 0), date(2023, 6, 4, 12, 0, 0)},
+		// either the 13th or a friday
+		{"0 0 13 * 5", date(2023, 6, 1, 0, 0, 0), date(2023, 6, 2, 0, 0, 0)},
+		{"0 0 13 * 5", date(2023, 6, 10, 0, 0, 0), date(2023, 6, 13, 0, 0, 0)},
+		// but a step over * is unrestricted, so the 13th has to fall on an even day of the week
+		{"0 0 13 * */2", date(2023, 6, 1, 0, 0, 0), date(2023, 6, 13, 0, 0, 0)},
+		{"0 0 13 * */2", date(2023, 6, 14, 0, 0, 0), date(2023, 7, 13, 0, 0, 0)},
+		{"0 0 29 2 *", date(2023, 3, 1, 0, 0, 0), date(2024, 2, 29, 0, 0, 0)},
+		{"0 0 31 * *", date(2023, 4, 1, 0, 0, 0), date(2023, 5, 31, 0, 0, 0)},
+		{"0 0 30 2 *", date(2023, 1, 1, 0, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		c := MustParse(tt.spec, time.UTC)
+		if got := c.Next(tt.after); !got.Equal(tt.want) {
+			t.Errorf("%q after %v: got %v, want %v", tt.spec, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestNextDst(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(mo time.Month, d, h, mi int) time.Time {
+		return time.Date(2023, mo, d, h, mi, 0, 0, ny)
+	}
+
+	// 7 o'clock is still 7 o'clock after the clocks change
+	daily := MustParse("0 7 * * *", ny)
+	springForward := daily.Next(date(3, 11, 7, 0))
+	if want := date(3, 12, 7, 0); !springForward.Equal(want) || springForward.Sub(date(3, 11, 7, 0)) != 23*time.Hour {
+		t.Errorf("got %v, want %v", springForward, want)
+	}
+	fallBack := daily.Next(date(11, 4, 7, 0))
+	if want := date(11, 5, 7, 0); !fallBack.Equal(want) || fallBack.Sub(date(11, 4, 7, 0)) != 25*time.Hour {
+		t.Errorf("got %v, want %v", fallBack, want)
+	}
+
+	// 2:30 doesn't exist on march 12th, so it runs at the jump to 3:00 instead
+	skipped := MustParse("30 2 * * *", ny)
+	first := skipped.Next(date(3, 12, 0, 0))
+	if want := date(3, 12, 3, 0); !first.Equal(want) {
+		t.Errorf("got %v, want %v", first, want)
+	}
+	if got, want := skipped.Next(first), date(3, 13, 2, 30); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// 1:30 happens twice on november 5th, but only runs the first time
+	repeated := MustParse("30 1 * * *", ny)
+	first = repeated.Next(date(11, 5, 0, 0))
+	if want := time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC); !first.Equal(want) {
+		t.Errorf("got %v, want %v", first, want)
+	}
+	if got, want := repeated.Next(first), date(11, 6, 1, 30); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// even when asked during the second time around
+	secondTime := time.Date(2023, 11, 5, 6, 10, 0, 0, time.UTC)
+	if got, want := repeated.Next(secondTime), date(11, 6, 1, 30); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
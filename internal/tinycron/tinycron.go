@@ -1,36 +1,234 @@
+// Package tinycron runs named jobs on cron expressions or intervals
 package tinycron
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
 	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
 )
 
-// EveryDay is a non blocking function that runs f once a day at the top of the given hour [0-23], and also when first called
-func EveryDay(ctx context.Context, hour int, f func()) {
-	go func() {
-		timer := time.NewTimer(time.Hour)
-		defer timer.Stop()
-		for {
-			f()
+// RetryPolicy reruns a job that didn't succeed, a zero RetryPolicy never retries
+type RetryPolicy struct {
+	// Every is how long to wait between tries
+	Every time.Duration
+	// For is how long after the first try to give up
+	For time.Duration
+}
+
+// MissedPolicy is what to do with a run that should have happened
+// a while ago, like when the machine was suspended
+type MissedPolicy int
 
-			now := time.Now()
+const (
+	// RunMissed runs the job once, as soon as possible, however many runs were missed
+	RunMissed MissedPolicy = iota
+	// SkipMissed waits for the next scheduled run instead
+	SkipMissed
+)
+
+// missedAfter is how late a run can be before it counts as missed
+const missedAfter = 5 * time.Minute
 
-			// overwrite now with the given hour
-			t := time.Date(now.Year(), now.Month(), now.Day(), hour, now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+// maxSleep keeps the scheduler looking at the clock, because timers
+// don't count the time the machine spends suspended
+const maxSleep = time.Minute
+
+type Job struct {
+	// Name is unique within a Scheduler
+	Name     string
+	Schedule Schedule
+	// Jitter delays every run by a random amount up to Jitter
+	Jitter time.Duration
+	Missed MissedPolicy
+	Retry  RetryPolicy
+	// RunOnStart runs the job right away, before following the Schedule
+	RunOnStart bool
+	// Run reports whether it succeeded, failed runs are retried according to Retry
+	Run func() bool
+}
 
-			// if it was in the past, add a day
-			if !t.After(now) {
-				t = t.AddDate(0, 0, 1)
+// JobStatus is for seeing what a Scheduler is up to
+type JobStatus struct {
+	Name     string
+	Schedule string
+	// LastRun is zero until the job first runs
+	LastRun time.Time
+	// NextRun is zero while the job is running, and once it will never run again
+	NextRun time.Time
+}
+
+type job struct {
+	Job
+	lastRun time.Time
+	nextRun time.Time
+}
+
+// Scheduler runs jobs in the background, each in its own goroutine
+type Scheduler struct {
+	clock clock.Clock
+	// jitter picks a delay up to max, tests replace it
+	jitter func(max time.Duration) time.Duration
+	m      sync.Mutex
+	jobs   []*job
+}
+
+func New(clk clock.Clock) *Scheduler {
+	return &Scheduler{
+		clock: clk,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
 			}
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+	}
+}
+
+// Add is non blocking, and runs j until ctx is done
+func (s *Scheduler) Add(ctx context.Context, j Job) error {
+	if j.Schedule == nil || j.Run == nil {
+		return errors.New("job needs a Schedule and a Run")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, other := range s.jobs {
+		if other.Name == j.Name {
+			return fmt.Errorf("job %q already exists", j.Name)
+		}
+	}
+
+	jb := &job{Job: j}
+	s.jobs = append(s.jobs, jb)
+
+	go s.run(ctx, jb)
+
+	return nil
+}
+
+// Next is when the named job runs next
+func (s *Scheduler) Next(name string) (time.Time, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, j := range s.jobs {
+		if j.Name == name {
+			return j.nextRun, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Jobs lists every job, in the order they were added
+func (s *Scheduler) Jobs() []JobStatus {
+	s.m.Lock()
+	defer s.m.Unlock()
 
-			timer.Reset(time.Until(t))
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, JobStatus{
+			Name:     j.Name,
+			Schedule: fmt.Sprint(j.Schedule),
+			LastRun:  j.lastRun,
+			NextRun:  j.nextRun,
+		})
+	}
+
+	return statuses
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	if j.RunOnStart && !s.runWithRetry(ctx, j) {
+		return
+	}
+
+	for {
+		next, ok := s.plan(j)
+		if !ok {
+			slog.Warn("Job will never run again", slog.String("job", j.Name))
+			return
+		}
+
+		for {
+			now := s.clock.Now()
+			if !now.Before(next) {
+				break
+			}
+
+			wait := next.Sub(now)
+			if wait > maxSleep {
+				wait = maxSleep
+			}
 
 			select {
 			case <-ctx.Done():
 				return
-			case <-timer.C:
+			case <-s.clock.After(wait):
+			}
+		}
+
+		if late := s.clock.Now().Sub(next); late > missedAfter {
+			slog.Warn("Missed job run",
+				slog.String("job", j.Name),
+				slog.Time("scheduled", next),
+				slog.Duration("late", late),
+			)
+
+			if j.Missed == SkipMissed {
 				continue
 			}
 		}
-	}()
+
+		if !s.runWithRetry(ctx, j) {
+			return
+		}
+	}
+}
+
+// plan picks the next run after now, jitter included
+func (s *Scheduler) plan(j *job) (time.Time, bool) {
+	next := j.Schedule.Next(s.clock.Now())
+	if next.IsZero() {
+		return next, false
+	}
+	next = next.Add(s.jitter(j.Jitter))
+
+	s.m.Lock()
+	j.nextRun = next
+	s.m.Unlock()
+
+	slog.Info("Next job run", slog.String("job", j.Name), slog.Time("next", next))
+
+	return next, true
+}
+
+// runWithRetry returns false once ctx is done
+func (s *Scheduler) runWithRetry(ctx context.Context, j *job) bool {
+	start := s.clock.Now()
+
+	s.m.Lock()
+	j.lastRun = start
+	j.nextRun = time.Time{}
+	s.m.Unlock()
+
+	ok := j.Run()
+	for !ok && j.Retry.Every > 0 && s.clock.Now().Sub(start)+j.Retry.Every <= j.Retry.For {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-s.clock.After(j.Retry.Every):
+		}
+
+		ok = j.Run()
+	}
+
+	return ctx.Err() == nil
 }
@@ -0,0 +1,239 @@
+package tinycron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0queue/mlb-rss/internal/clock"
+)
+
+// start is a bit after six, with some seconds to make sure they are dropped
+var start = time.Date(2023, 6, 1, 6, 20, 13, 0, time.UTC)
+
+var seven = time.Date(2023, 6, 1, 7, 0, 0, 0, time.UTC)
+
+func newTestScheduler(t *testing.T) (*Scheduler, *clock.Fake, context.Context) {
+	clk := clock.NewFake(start)
+	s := New(clk)
+	s.jitter = func(max time.Duration) time.Duration { return max / 2 }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return s, clk, ctx
+}
+
+// runs counts runs, and returns results from ok in order (then true)
+func runs(ok ...bool) (chan time.Time, func(clk clock.Clock) func() bool) {
+	c := make(chan time.Time, 10)
+	return c, func(clk clock.Clock) func() bool {
+		return func() bool {
+			c <- clk.Now()
+			if len(ok) == 0 {
+				return true
+			}
+			result := ok[0]
+			ok = ok[1:]
+			return result
+		}
+	}
+}
+
+func waitRun(t *testing.T, c chan time.Time, want time.Time) {
+	t.Helper()
+
+	select {
+	case got := <-c:
+		if !got.Equal(want) {
+			t.Errorf("ran at %v, want %v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("did not run, wanted a run at %v", want)
+	}
+}
+
+func noRun(t *testing.T, c chan time.Time) {
+	t.Helper()
+
+	select {
+	case got := <-c:
+		t.Errorf("ran at %v, want no run", got)
+	default:
+	}
+}
+
+func wantNext(t *testing.T, s *Scheduler, name string, want time.Time) {
+	t.Helper()
+
+	got, ok := s.Next(name)
+	if !ok {
+		t.Fatalf("no job %s", name)
+	}
+	if !got.Equal(want) {
+		t.Errorf("next run at %v, want %v", got, want)
+	}
+}
+
+func TestDaily(t *testing.T) {
+	s, clk, ctx := newTestScheduler(t)
+	c, run := runs()
+
+	err := s.Add(ctx, Job{Name: "daily", Schedule: MustParse("0 7 * * *", time.UTC), Run: run(clk)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk.BlockUntil(1)
+	wantNext(t, s, "daily", seven)
+
+	clk.Set(seven)
+	waitRun(t, c, seven)
+
+	clk.BlockUntil(1)
+	wantNext(t, s, "daily", seven.AddDate(0, 0, 1))
+
+	clk.Set(seven.AddDate(0, 0, 1))
+	waitRun(t, c, seven.AddDate(0, 0, 1))
+}
+
+func TestRunOnStartAndJitter(t *testing.T) {
+	s, clk, ctx := newTestScheduler(t)
+	c, run := runs()
+
+	err := s.Add(ctx, Job{
+		Name:       "jittery",
+		Schedule:   MustParse("0 7 * * *", time.UTC),
+		Jitter:     30 * time.Minute,
+		RunOnStart: true,
+		Run:        run(clk),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitRun(t, c, start)
+
+	clk.BlockUntil(1)
+	wantNext(t, s, "jittery", seven.Add(15*time.Minute))
+
+	// not yet
+	clk.Set(seven)
+	clk.BlockUntil(1)
+	noRun(t, c)
+
+	clk.Set(seven.Add(15 * time.Minute))
+	waitRun(t, c, seven.Add(15*time.Minute))
+}
+
+func TestMissed(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy MissedPolicy
+		run    bool
+	}{
+		{"run", RunMissed, true},
+		{"skip", SkipMissed, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s, clk, ctx := newTestScheduler(t)
+			c, run := runs()
+
+			err := s.Add(ctx, Job{
+				Name:     "missed",
+				Schedule: MustParse("0 7 * * *", time.UTC),
+				Missed:   tt.policy,
+				Run:      run(clk),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// like waking up from a suspend, long after the run was due
+			clk.BlockUntil(1)
+			late := seven.Add(3 * time.Hour)
+			clk.Set(late)
+
+			if tt.run {
+				waitRun(t, c, late)
+			}
+
+			clk.BlockUntil(1)
+			noRun(t, c)
+			wantNext(t, s, "missed", seven.AddDate(0, 0, 1))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	s, clk, ctx := newTestScheduler(t)
+	c, run := runs(false, false, true)
+
+	err := s.Add(ctx, Job{
+		Name:     "retry",
+		Schedule: MustParse("0 7 * * *", time.UTC),
+		Retry:    RetryPolicy{Every: 15 * time.Minute, For: time.Hour},
+		Run:      run(clk),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk.BlockUntil(1)
+	clk.Set(seven)
+	waitRun(t, c, seven)
+
+	for i := 1; i <= 2; i++ {
+		clk.BlockUntil(1)
+		clk.Advance(15 * time.Minute)
+		waitRun(t, c, seven.Add(time.Duration(i)*15*time.Minute))
+	}
+
+	clk.BlockUntil(1)
+	wantNext(t, s, "retry", seven.AddDate(0, 0, 1))
+}
+
+func TestInterval(t *testing.T) {
+	s, clk, ctx := newTestScheduler(t)
+	c, run := runs()
+
+	err := s.Add(ctx, Job{Name: "interval", Schedule: Interval(30 * time.Minute), Run: run(clk)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		clk.BlockUntil(1)
+		wantNext(t, s, "interval", start.Add(time.Duration(i)*30*time.Minute))
+		clk.Set(start.Add(time.Duration(i) * 30 * time.Minute))
+		waitRun(t, c, start.Add(time.Duration(i)*30*time.Minute))
+	}
+}
+
+func TestJobs(t *testing.T) {
+	s, clk, ctx := newTestScheduler(t)
+	_, run := runs()
+
+	err := s.Add(ctx, Job{Name: "a", Schedule: MustParse("0 7 * * *", time.UTC), Run: run(clk)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Add(ctx, Job{Name: "a", Schedule: Interval(time.Minute), Run: run(clk)})
+	if err == nil {
+		t.Error("added a job with the same name twice")
+	}
+
+	clk.BlockUntil(1)
+	jobs := s.Jobs()
+	if len(jobs) != 1 || jobs[0].Name != "a" || jobs[0].Schedule != "0 7 * * *" || !jobs[0].NextRun.Equal(seven) {
+		t.Errorf("got %+v", jobs)
+	}
+
+	if _, ok := s.Next("b"); ok {
+		t.Error("found a job that doesn't exist")
+	}
+}
@@ -0,0 +1,65 @@
+{{ define "pitchingLine" }}
+<tr>
+<td>{{ .Abbr }}</td>
+<td>{{ .Name }} {{ .Note }}</td>
+<td>{{ .InningsPitched }}</td>
+<td>{{ .Hits }}</td>
+<td>{{ .Runs }}</td>
+<td>{{ .EarnedRuns }}</td>
+<td>{{ .BaseOnBalls }}</td>
+<td>{{ .StrikeOuts }}</td>
+</tr>
+{{ end }}
+
+{{ define "boxscore" }}
+<table>
+	<tr>
+		<th></th>
+		<th>Pitching</th>
+		<th>IP</th>
+		<th>H</th>
+		<th>R</th>
+		<th>ER</th>
+		<th>BB</th>
+		<th>K</th>
+	</tr>
+
+	{{ range .Starters }}
+	{{ template "pitchingLine" . }}
+	{{ end }}
+
+	{{ if .Decisions }}
+	<tr><td></td><td><i>Decisions</i></td></tr>
+	{{ range .Decisions }}
+	{{ template "pitchingLine" . }}
+	{{ end }}
+	{{ end }}
+</table>
+
+{{ if .TopHitters }}
+<br>
+<table>
+	<tr>
+		<th>Batting</th>
+		<th>AB</th>
+		<th>R</th>
+		<th>H</th>
+		<th>HR</th>
+		<th>RBI</th>
+		<th>BB</th>
+	</tr>
+
+	{{ range .TopHitters }}
+	<tr>
+		<td>{{ .Name }}</td>
+		<td>{{ .AtBats }}</td>
+		<td>{{ .Runs }}</td>
+		<td>{{ .Hits }}</td>
+		<td>{{ .HomeRuns }}</td>
+		<td>{{ .Rbi }}</td>
+		<td>{{ .BaseOnBalls }}</td>
+	</tr>
+	{{ end }}
+</table>
+{{ end }}
+{{ end }}
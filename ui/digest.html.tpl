@@ -0,0 +1,12 @@
+{{ define "digest" }}
+<strong>This week's moves</strong>
+
+{{ range .Groups }}
+<p>{{ .Name }}</p>
+<ul>
+	{{ range .Moves }}
+	<li>{{ .Day }}: {{ .Description }}</li>
+	{{ end }}
+</ul>
+{{ end }}
+{{ end }}
@@ -0,0 +1,5 @@
+{{ define "offseason" }}
+<strong>Offseason</strong>
+
+<p>{{ if eq .DaysUntilSpring 1 }}Spring training starts tomorrow{{ else }}{{ .DaysUntilSpring }} days until spring training{{ end }}, the first game of {{ .Season }} is on {{ .SpringStart }}</p>
+{{ end }}
@@ -1,12 +1,40 @@
 {{ define "pastGame" }}
 {{ if .PostponeReason }}
 <p>The game was postponed due to {{ .PostponeReason }} at {{ .Venue.Name }}</p>
+{{ else if eq .State "postponed" }}
+<p>The game was postponed at {{ .Venue.Name }}</p>
+{{ else if eq .State "cancelled" }}
+<p>The game was cancelled {{- if .Reason }} due to {{ .Reason }}{{ end }} at {{ .Venue.Name }}</p>
+{{ else if eq .State "notStarted" }}
+<p>The {{ .Away.Team.Name }} and the {{ .Home.Team.Name }} had not started yet when this report was made</p>
+{{ else }}
+{{ if eq .State "suspended" }}
+<p>
+The game between the {{ .Away.Team.Name }} and the {{ .Home.Team.Name }}
+was suspended {{- if .Reason }} due to {{ .Reason }}{{ end }}
+with the score {{ .Away.Score }} to {{ .Home.Score }}.
+{{ if .ResumesAt }}It resumes {{ .ResumesAt }}.{{ else }}It has not been rescheduled yet.{{ end }}
+</p>
+{{ else if eq .State "delayed" }}
+<p>
+The game between the {{ .Away.Team.Name }} and the {{ .Home.Team.Name }}
+was delayed {{- if .Reason }} due to {{ .Reason }}{{ end }}
+with the score {{ .Away.Score }} to {{ .Home.Score }} when this report was made.
+</p>
+{{ else if eq .State "inProgress" }}
+<p>
+The {{ .Away.Team.Name }} and the {{ .Home.Team.Name }} were still playing
+when this report was made, {{ .Away.Score }} to {{ .Home.Score }}.
+</p>
 {{ else }}
 <p>
 The {{ .W.Team.Name }} ({{ .W.LeagueRecord.Wins }} - {{ .W.LeagueRecord.Losses }})
 beat the {{ .L.Team.Name }} ({{ .L.LeagueRecord.Wins }} - {{ .L.LeagueRecord.Losses }})
 {{ .W.Score }} to {{ .L.Score}} {{- if .IsWinnerHome }} at home. {{ else }} on the road. {{ end }}
+{{ if eq .State "completedEarly" }}The game was called early {{- if .Reason }} due to {{ .Reason }}{{ end }}.{{ end }}
+{{ if .ResumedFrom }}The game was suspended on {{ .ResumedFrom }}, and finished yesterday.{{ end }}
 </p>
+{{ end }}
 {{ if .HasLinescore }}
 
 <table>
@@ -26,7 +54,14 @@ beat the {{ .L.Team.Name }} ({{ .L.LeagueRecord.Wins }} - {{ .L.LeagueRecord.Los
 </table>
 
 {{ end }}
-{{ if and .HasLinescore .CondensedGameUrl }}<br>{{ end }}
+{{ if .HasBoxscore }}
+{{ if .HasLinescore }}<br>{{ end }}
+{{ template "boxscore" .Boxscore }}
+{{ end }}
+{{ if .ScoringPlays }}
+{{ template "scoringPlays" .ScoringPlays }}
+{{ end }}
+{{ if and (or .HasLinescore .HasBoxscore) .CondensedGameUrl }}<br>{{ end }}
 {{ if .CondensedGameUrl }}
 <video controls width="650">
 	<source src="{{ .CondensedGameUrl }}">
@@ -0,0 +1,20 @@
+{{ define "postseason" }}
+<strong>Postseason</strong>
+
+<table>
+	{{ range .Series }}
+	<tr>
+		<td>{{ .Name }}</td>
+		{{ range .Teams }}
+		{{ if .IsMyTeam }}
+		<td><strong>{{ .Abbr }} {{ .Wins }}</strong></td>
+		{{ else }}
+		<td>{{ .Abbr }} {{ .Wins }}</td>
+		{{ end }}
+		{{ end }}
+		<td>{{ .Status }}</td>
+		<td>{{ .NextGame }}</td>
+	</tr>
+	{{ end }}
+</table>
+{{ end }}
@@ -1,2 +1,7 @@
+{{ if .HasOffseason }}{{ template "offseason" .Offseason }}{{ end -}}
+{{ if .HasDigest }}{{ template "digest" .Digest }}{{ end -}}
 {{ template "yesterday" .Yesterday }}
+{{ if .HasRosterMoves }}{{ template "rosterMoves" .RosterMoves }}{{ end -}}
 {{ template "upcoming" .Upcoming }}
+{{ if .HasPostseason }}{{ template "postseason" .Postseason }}{{ end }}
+{{ if .HasStandings }}{{ template "standings" .Standings }}{{ end }}
@@ -0,0 +1,9 @@
+{{ define "rosterMoves" }}
+<strong>Roster moves</strong>
+
+<ul>
+	{{ range . }}
+	<li>{{ .Move }}: {{ .Position }} {{ .Name }}{{ if .Details }}<br><small>{{ .Details }}</small>{{ end }}</li>
+	{{ end }}
+</ul>
+{{ end }}
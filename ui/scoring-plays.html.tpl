@@ -0,0 +1,8 @@
+{{ define "scoringPlays" }}
+<i>How it happened</i>
+<ul>
+	{{ range . }}
+	<li>{{ .Inning }}: {{ .Description }} <strong>{{ .Score }}</strong></li>
+	{{ end }}
+</ul>
+{{ end }}
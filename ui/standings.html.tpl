@@ -0,0 +1,39 @@
+{{ define "standingsTable" }}
+<table>
+	<tr>
+		<th>{{ .Name }}</th>
+		<th>W</th>
+		<th>L</th>
+		<th>Pct</th>
+		<th>GB</th>
+		<th>L10</th>
+		<th>Strk</th>
+		<th>RD</th>
+	</tr>
+
+	{{ range .Rows }}
+	<tr>
+		{{ if .IsMyTeam }}
+		<td><strong>{{ .Abbr }}</strong></td>
+		{{ else }}
+		<td>{{ .Abbr }}</td>
+		{{ end }}
+		<td>{{ .Wins }}</td>
+		<td>{{ .Losses }}</td>
+		<td>{{ .Pct }}</td>
+		<td>{{ .GamesBack }}</td>
+		<td>{{ .LastTen }}</td>
+		<td>{{ .Streak }}</td>
+		<td>{{ signed .RunDifferential }}</td>
+	</tr>
+	{{ end }}
+</table>
+{{ end }}
+
+{{ define "standings" }}
+<strong>Standings</strong>
+
+{{ template "standingsTable" .Division }}
+<br>
+{{ template "standingsTable" .WildCard }}
+{{ end }}
@@ -37,6 +37,19 @@
 		{{ end }}
 		{{ end }}
 	</tr>
+	{{- if .HasProbables }}
+
+	<tr>
+		{{ range .FutureDays }}
+		<td>
+		{{ range $i, $g := .Games }}
+			{{ if $i }}<br>{{ end }}
+			{{ if $g.HasProbables }}{{ $g.Matchup }}{{ with $g.Records }}<br><small>{{ . }}</small>{{ end }}{{ end }}
+		{{ end }}
+		</td>
+		{{ end }}
+	</tr>
+	{{- end }}
 </table>
 
 <!-- yeah yeah this doesn't work in miniflux (unless I trust the site?) -->
@@ -7,7 +7,12 @@
 </head>
 <body>
 <h2>{{ .H2 }}</h2>
+{{ if .HasOffseason }}{{ template "offseason" .Offseason }}{{ end -}}
+{{ if .HasDigest }}{{ template "digest" .Digest }}{{ end -}}
 {{ template "yesterday" .Yesterday }}
+{{ if .HasRosterMoves }}{{ template "rosterMoves" .RosterMoves }}{{ end -}}
 {{ template "upcoming" .Upcoming }}
+{{ if .HasPostseason }}{{ template "postseason" .Postseason }}{{ end }}
+{{ if .HasStandings }}{{ template "standings" .Standings }}{{ end }}
 </body>
 </html>
\ No newline at end of file
@@ -6,7 +6,7 @@
 {{ template "pastGame" (index .PastGames 0) }}
 {{ else }}
 {{ range $i, $pastGame := .PastGames }}
-<i>Game {{ $i }}</i>
+<i>Game {{ inc $i }}</i>
 {{ template "pastGame" $pastGame}}
 {{ end }}
 {{ end }}